@@ -0,0 +1,167 @@
+// Command graphqlgen generates typed Go code for GraphQL operations.
+//
+// It reads operations and fragments from .graphql files, validates them against
+// a local copy of the schema in the GraphQL schema definition language, and
+// writes a Go file with typed variables and responses and a function for each
+// operation that executes it with api.GraphQLClient.
+//
+// Usage:
+//
+//	//go:generate go run github.com/cli/go-gh/v2/cmd/graphqlgen -schema schema.graphql -o queries_gen.go queries/*.graphql
+//
+// Flags:
+//
+//	-schema path    schema file, such as GitHub's public schema.docs.graphql
+//	-o path         output file, or standard output if empty
+//	-package name   package of the generated code, defaults to $GOPACKAGE
+//	-scalar S=T     map the custom scalar S to the Go type T, such as
+//	                DateTime=time.Time; may be repeated
+//
+// Arguments are .graphql files, glob patterns, or directories
+// containing .graphql files.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cli/go-gh/v2/internal/graphql"
+)
+
+type scalarFlag map[string]string
+
+func (f scalarFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (f scalarFlag) Set(value string) error {
+	name, goType, ok := strings.Cut(value, "=")
+	if !ok || name == "" || goType == "" {
+		return fmt.Errorf("expected SCALAR=TYPE, got %q", value)
+	}
+	f[name] = goType
+	return nil
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "graphqlgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("graphqlgen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	schemaPath := flags.String("schema", "", "schema file in the GraphQL schema definition language")
+	output := flags.String("o", "", "output file, or standard output if empty")
+	pkg := flags.String("package", os.Getenv("GOPACKAGE"), "package of the generated code")
+	scalars := scalarFlag{}
+	flags.Var(scalars, "scalar", "map a custom scalar to a Go type, such as DateTime=time.Time")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *schemaPath == "" {
+		return errors.New("-schema is required")
+	}
+	if *pkg == "" {
+		return errors.New("-package is required outside of go generate")
+	}
+
+	files, err := expandPaths(flags.Args())
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return errors.New("no .graphql files given")
+	}
+
+	schemaSrc, err := os.ReadFile(*schemaPath)
+	if err != nil {
+		return err
+	}
+	schema, err := graphql.ParseSchema(*schemaPath, string(schemaSrc))
+	if err != nil {
+		return err
+	}
+
+	doc := &graphql.Document{}
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		d, err := graphql.ParseQuery(file, string(src))
+		if err != nil {
+			return err
+		}
+		doc.Operations = append(doc.Operations, d.Operations...)
+		doc.Fragments = append(doc.Fragments, d.Fragments...)
+	}
+
+	src, err := graphql.Generate(schema, doc, graphql.GenerateOptions{Package: *pkg, Scalars: scalars})
+	if err != nil {
+		var errs graphql.Errors
+		if errors.As(err, &errs) {
+			return fmt.Errorf("invalid operations:\n%w", err)
+		}
+		return err
+	}
+
+	if *output == "" {
+		_, err = stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*output, src, 0644)
+}
+
+// expandPaths expands glob patterns and directories to a sorted list of files.
+func expandPaths(args []string) ([]string, error) {
+	seen := map[string]bool{}
+	var files []string
+	add := func(file string) {
+		if !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+	for _, arg := range args {
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, err
+		}
+		if matches == nil {
+			return nil, fmt.Errorf("%s: no such file or directory", arg)
+		}
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, err
+			}
+			if !info.IsDir() {
+				add(match)
+				continue
+			}
+			dirMatches, err := filepath.Glob(filepath.Join(match, "*.graphql"))
+			if err != nil {
+				return nil, err
+			}
+			for _, m := range dirMatches {
+				add(m)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
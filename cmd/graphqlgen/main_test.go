@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const schema = `
+type Query {
+  viewer: User!
+}
+
+type User {
+  login: String!
+  createdAt: DateTime!
+}
+
+scalar DateTime
+`
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "schema.graphql")
+	assert.NoError(t, os.WriteFile(schemaPath, []byte(schema), 0644))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "queries"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "queries", "viewer.graphql"), []byte("query Viewer { viewer { login createdAt } }"), 0644))
+	output := filepath.Join(dir, "queries_gen.go")
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	err := run([]string{"-schema", schemaPath, "-package", "queries", "-scalar", "DateTime=time.Time", "-o", output, filepath.Join(dir, "queries")}, stdout, stderr)
+	assert.NoError(t, err)
+	assert.Equal(t, "", stdout.String())
+
+	src, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), "package queries")
+	assert.Contains(t, string(src), "func Viewer(ctx context.Context, client *api.GraphQLClient) (*ViewerResponse, error) {")
+	assert.Contains(t, string(src), "CreatedAt time.Time `json:\"createdAt\"`")
+}
+
+func TestRunInvalidOperation(t *testing.T) {
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "schema.graphql")
+	assert.NoError(t, os.WriteFile(schemaPath, []byte(schema), 0644))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "queries"), 0755))
+	queryPath := filepath.Join(dir, "queries", "viewer.graphql")
+	assert.NoError(t, os.WriteFile(queryPath, []byte("query Viewer {\n  viewer { name }\n}"), 0644))
+
+	err := run([]string{"-schema", schemaPath, "-package", "queries", filepath.Join(dir, "queries", "*.graphql")}, &bytes.Buffer{}, &bytes.Buffer{})
+	assert.EqualError(t, err, "invalid operations:\n"+queryPath+`:2:12: field "name" does not exist on type "User"`)
+}
+
+func TestRunMissingFlags(t *testing.T) {
+	err := run([]string{"-package", "queries", "q.graphql"}, &bytes.Buffer{}, &bytes.Buffer{})
+	assert.EqualError(t, err, "-schema is required")
+
+	err = run([]string{"-schema", "schema.graphql", "-package", "queries", "-scalar", "DateTime"}, &bytes.Buffer{}, &bytes.Buffer{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `expected SCALAR=TYPE, got "DateTime"`)
+}
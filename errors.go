@@ -0,0 +1,98 @@
+package gh
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// Exit codes used by gh to report why a command failed.
+const (
+	// ExitError is the exit code of a command that failed.
+	ExitError = 1
+	// ExitCancel is the exit code of a command that was cancelled by the user.
+	ExitCancel = 2
+	// ExitAuth is the exit code of a command that requires authentication.
+	ExitAuth = 4
+)
+
+// maxStderrSize is the amount of stderr output retained by ExecError.
+const maxStderrSize = 64 * 1024
+
+// ExecError is returned by the Exec functions when a gh command fails.
+type ExecError struct {
+	// Args are the arguments the command was invoked with.
+	Args []string
+
+	// ExitCode is the exit code of the command,
+	// or -1 if it did not start or was terminated by a signal.
+	ExitCode int
+
+	// Stderr is the error output of the command. Only the last 64 KiB
+	// are retained for commands that write more.
+	Stderr string
+
+	// Canceled reports whether the command was stopped because its context was done.
+	Canceled bool
+
+	err error
+}
+
+// Allow ExecError to satisfy error interface.
+func (e *ExecError) Error() string {
+	return "gh execution failed: " + e.err.Error()
+}
+
+// Unwrap returns the underlying error, such as an *exec.ExitError
+// or the error of the command's context.
+func (e *ExecError) Unwrap() error {
+	return e.err
+}
+
+// IsAuthError reports whether the command failed because gh is not authenticated.
+func (e *ExecError) IsAuthError() bool {
+	return e.ExitCode == ExitAuth
+}
+
+// IsCancel reports whether the command was cancelled, either by its
+// context or by the user.
+func (e *ExecError) IsCancel() bool {
+	return e.Canceled || e.ExitCode == ExitCancel
+}
+
+func newExecError(args []string, err error, stderr string, canceled bool) *ExecError {
+	e := &ExecError{
+		Args:     args,
+		ExitCode: -1,
+		Stderr:   stderr,
+		Canceled: canceled,
+		err:      err,
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		e.ExitCode = exitErr.ExitCode()
+	}
+	return e
+}
+
+// tailBuffer is a writer that retains the last max bytes written to it.
+type tailBuffer struct {
+	max int
+	buf []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(p) > b.max {
+		p = p[len(p)-b.max:]
+	}
+	if overflow := len(b.buf) + len(p) - b.max; overflow > 0 {
+		b.buf = append(b.buf[:0], b.buf[overflow:]...)
+	}
+	b.buf = append(b.buf, p...)
+	return n, nil
+}
+
+func (b *tailBuffer) String() string {
+	return strings.ToValidUTF8(string(b.buf), "")
+}
@@ -1,6 +1,7 @@
 package gh_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -29,6 +30,36 @@ func ExampleExec() {
 	fmt.Println(stdErr.String())
 }
 
+// Execute 'gh run watch' against a GitHub Enterprise Server host,
+// streaming its output as the run progresses.
+func ExampleExecWithOptions() {
+	opts := gh.ExecOptions{
+		Env:    []string{"GH_HOST=github.example.com", "GH_REPO=octo-org/octo-repo"},
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+	err := gh.ExecWithOptions(context.Background(), opts, "run", "watch", "1234")
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// List the open pull requests of cli/cli with 'gh pr list --json'.
+func ExampleExecJSON() {
+	var prs []struct {
+		Number int
+		Title  string
+	}
+	opts := gh.ExecJSONOptions{Fields: []string{"number", "title"}}
+	err := gh.ExecJSON(context.Background(), opts, &prs, "pr", "list", "-R", "cli/cli")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, pr := range prs {
+		fmt.Printf("#%d %s\n", pr.Number, pr.Title)
+	}
+}
+
 // Get tags from cli/cli repository using REST API.
 func ExampleDefaultRESTClient() {
 	client, err := api.DefaultRESTClient()
@@ -8,31 +8,36 @@ package gh
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/cli/safeexec"
 )
 
 // Exec invokes a gh command in a subprocess and captures the output and error streams.
+// If the command fails, the returned error is an *ExecError.
 func Exec(args ...string) (stdout, stderr bytes.Buffer, err error) {
 	ghExe, err := Path()
 	if err != nil {
 		return
 	}
-	err = run(context.Background(), ghExe, nil, nil, &stdout, &stderr, args)
+	err = run(context.Background(), ghExe, ExecOptions{Stdout: &stdout, Stderr: &stderr}, args)
 	return
 }
 
 // ExecContext invokes a gh command in a subprocess and captures the output and error streams.
+// If the command fails, the returned error is an *ExecError.
 func ExecContext(ctx context.Context, args ...string) (stdout, stderr bytes.Buffer, err error) {
 	ghExe, err := Path()
 	if err != nil {
 		return
 	}
-	err = run(ctx, ghExe, nil, nil, &stdout, &stderr, args)
+	err = run(ctx, ghExe, ExecOptions{Stdout: &stdout, Stderr: &stderr}, args)
 	return
 }
 
@@ -43,7 +48,87 @@ func ExecInteractive(ctx context.Context, args ...string) error {
 	if err != nil {
 		return err
 	}
-	return run(ctx, ghExe, nil, os.Stdin, os.Stdout, os.Stderr, args)
+	return run(ctx, ghExe, ExecOptions{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}, args)
+}
+
+// ExecOptions holds options for running a gh command with ExecWithOptions.
+type ExecOptions struct {
+	// Env holds environment variables in the form "KEY=value", such as
+	// "GH_HOST=example.com" or "GH_REPO=cli/cli", that are set in addition
+	// to those inherited from the parent process, overriding any with the same key.
+	Env []string
+
+	// Stdin is the input of the command. If nil, the command reads from the null device.
+	Stdin io.Reader
+
+	// Stdout and Stderr receive the output of the command as it is written.
+	// If nil, the output is discarded.
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// Dir is the working directory of the command.
+	// If empty, the command runs in the current directory.
+	Dir string
+}
+
+// ExecWithOptions invokes a gh command in a subprocess configured by opts. Output is
+// written to the writers of opts as the command produces it, which makes it possible to
+// stream the output of long running commands. If the command fails, the returned
+// error is an *ExecError.
+func ExecWithOptions(ctx context.Context, opts ExecOptions, args ...string) error {
+	ghExe, err := Path()
+	if err != nil {
+		return err
+	}
+	return run(ctx, ghExe, opts, args)
+}
+
+// ExecJSONOptions holds options for running a gh command with ExecJSON.
+type ExecJSONOptions struct {
+	// Fields are the JSON fields to request with the --json flag. At least one is required.
+	Fields []string
+
+	// JQ is an optional expression passed with the --jq flag to filter the output.
+	// The expression must produce a single JSON value.
+	JQ string
+
+	// Template is an optional Go template passed with the --template flag to format the output.
+	// The template must produce a single JSON value.
+	Template string
+
+	// ExecOptions configures the subprocess. Its Stdout is ignored
+	// since the output of the command is decoded instead.
+	ExecOptions
+}
+
+// ExecJSON invokes a gh command that supports the --json flag, such as "pr list", and
+// decodes its output into v. If the command fails, the returned error is an *ExecError.
+func ExecJSON(ctx context.Context, opts ExecJSONOptions, v interface{}, args ...string) error {
+	if len(opts.Fields) == 0 {
+		return errors.New("at least one JSON field is required")
+	}
+	args = append(args[:len(args):len(args)], "--json", strings.Join(opts.Fields, ","))
+	if opts.JQ != "" {
+		args = append(args, "--jq", opts.JQ)
+	}
+	if opts.Template != "" {
+		args = append(args, "--template", opts.Template)
+	}
+
+	ghExe, err := Path()
+	if err != nil {
+		return err
+	}
+	var stdout bytes.Buffer
+	execOpts := opts.ExecOptions
+	execOpts.Stdout = &stdout
+	if err := run(ctx, ghExe, execOpts, args); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(stdout.Bytes(), v); err != nil {
+		return fmt.Errorf("failed to decode gh output: %w", err)
+	}
+	return nil
 }
 
 // Path searches for an executable named "gh" in the directories named by the PATH environment variable.
@@ -55,16 +140,23 @@ func Path() (string, error) {
 	return safeexec.LookPath("gh")
 }
 
-func run(ctx context.Context, ghExe string, env []string, stdin io.Reader, stdout, stderr io.Writer, args []string) error {
+func run(ctx context.Context, ghExe string, opts ExecOptions, args []string) error {
 	cmd := exec.CommandContext(ctx, ghExe, args...)
-	cmd.Stdin = stdin
-	cmd.Stdout = stdout
+	cmd.Stdin = opts.Stdin
+	cmd.Stdout = opts.Stdout
+	// Retain the error output for ExecError while still writing it to opts.Stderr.
+	stderr := &tailBuffer{max: maxStderrSize}
 	cmd.Stderr = stderr
-	if env != nil {
-		cmd.Env = env
+	if opts.Stderr != nil {
+		cmd.Stderr = io.MultiWriter(opts.Stderr, stderr)
+	}
+	cmd.Dir = opts.Dir
+	if opts.Env != nil {
+		// Later entries take precedence over earlier ones with the same key.
+		cmd.Env = append(os.Environ(), opts.Env...)
 	}
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("gh execution failed: %w", err)
+		return newExecError(args, err, stderr.String(), ctx.Err() != nil)
 	}
 	return nil
 }
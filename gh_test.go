@@ -3,8 +3,12 @@ package gh
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,8 +20,22 @@ func TestHelperProcess(t *testing.T) {
 		return
 	}
 	if err := func(args []string) error {
-		if args[len(args)-1] == "error" {
+		switch args[len(args)-1] {
+		case "error":
 			return fmt.Errorf("process exited with error")
+		case "auth":
+			fmt.Fprint(os.Stderr, "not logged in")
+			os.Exit(4)
+		case "env":
+			fmt.Fprint(os.Stdout, os.Getenv("GH_HOST"))
+			return nil
+		case "stdin":
+			_, err := io.Copy(os.Stdout, os.Stdin)
+			return err
+		case "pwd":
+			wd, err := os.Getwd()
+			fmt.Fprint(os.Stdout, wd)
+			return err
 		}
 		fmt.Fprintf(os.Stdout, "%v", args)
 		return nil
@@ -40,9 +58,22 @@ func TestHelperProcessLongRunning(t *testing.T) {
 	os.Exit(0)
 }
 
+func TestHelperProcessJSON(t *testing.T) {
+	if os.Getenv("GH_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	args := os.Args[3:]
+	if args[1] == "invalid" {
+		fmt.Fprint(os.Stdout, "not json")
+		os.Exit(0)
+	}
+	_ = json.NewEncoder(os.Stdout).Encode(map[string][]string{"args": args})
+	os.Exit(0)
+}
+
 func TestRun(t *testing.T) {
 	var stdout, stderr bytes.Buffer
-	err := run(context.TODO(), os.Args[0], []string{"GH_WANT_HELPER_PROCESS=1"}, nil, &stdout, &stderr,
+	err := run(context.TODO(), os.Args[0], ExecOptions{Env: []string{"GH_WANT_HELPER_PROCESS=1"}, Stdout: &stdout, Stderr: &stderr},
 		[]string{"-test.run=TestHelperProcess", "--", "gh", "issue", "list"})
 	assert.NoError(t, err)
 	assert.Equal(t, "[gh issue list]", stdout.String())
@@ -51,18 +82,166 @@ func TestRun(t *testing.T) {
 
 func TestRunError(t *testing.T) {
 	var stdout, stderr bytes.Buffer
-	err := run(context.TODO(), os.Args[0], []string{"GH_WANT_HELPER_PROCESS=1"}, nil, &stdout, &stderr,
+	err := run(context.TODO(), os.Args[0], ExecOptions{Env: []string{"GH_WANT_HELPER_PROCESS=1"}, Stdout: &stdout, Stderr: &stderr},
 		[]string{"-test.run=TestHelperProcess", "--", "gh", "error"})
 	assert.EqualError(t, err, "gh execution failed: exit status 1")
 	assert.Equal(t, "", stdout.String())
 	assert.Equal(t, "process exited with error", stderr.String())
+
+	var execErr *ExecError
+	assert.ErrorAs(t, err, &execErr)
+	assert.Equal(t, []string{"-test.run=TestHelperProcess", "--", "gh", "error"}, execErr.Args)
+	assert.Equal(t, ExitError, execErr.ExitCode)
+	assert.Equal(t, "process exited with error", execErr.Stderr)
+	assert.False(t, execErr.Canceled)
+	assert.False(t, execErr.IsAuthError())
+	assert.False(t, execErr.IsCancel())
+}
+
+func TestRunAuthError(t *testing.T) {
+	// Error output is retained even if it is not written anywhere else.
+	err := run(context.TODO(), os.Args[0], ExecOptions{Env: []string{"GH_WANT_HELPER_PROCESS=1"}},
+		[]string{"-test.run=TestHelperProcess", "--", "gh", "auth"})
+	var execErr *ExecError
+	assert.ErrorAs(t, err, &execErr)
+	assert.Equal(t, ExitAuth, execErr.ExitCode)
+	assert.Equal(t, "not logged in", execErr.Stderr)
+	assert.True(t, execErr.IsAuthError())
 }
 
 func TestRunInteractiveContextCanceled(t *testing.T) {
 	// pass current time to ensure that deadline has already passed
 	ctx, cancel := context.WithDeadline(context.Background(), time.Now())
 	cancel()
-	err := run(ctx, os.Args[0], []string{"GH_WANT_HELPER_PROCESS=1"}, nil, nil, nil,
+	err := run(ctx, os.Args[0], ExecOptions{Env: []string{"GH_WANT_HELPER_PROCESS=1"}},
 		[]string{"-test.run=TestHelperProcessLongRunning", "--", "gh", "issue", "list"})
 	assert.EqualError(t, err, "gh execution failed: context deadline exceeded")
+
+	var execErr *ExecError
+	assert.ErrorAs(t, err, &execErr)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, -1, execErr.ExitCode)
+	assert.True(t, execErr.Canceled)
+	assert.True(t, execErr.IsCancel())
+}
+
+func TestTailBuffer(t *testing.T) {
+	b := &tailBuffer{max: 5}
+	n, err := b.Write([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, "abc", b.String())
+
+	_, _ = b.Write([]byte("def"))
+	assert.Equal(t, "bcdef", b.String())
+
+	n, _ = b.Write([]byte("0123456789"))
+	assert.Equal(t, 10, n)
+	assert.Equal(t, "56789", b.String())
+}
+
+func TestRunWithOptions(t *testing.T) {
+	dir := t.TempDir()
+	// Resolve symlinks such as /tmp on macOS so the working directory can be compared.
+	dir, err := filepath.EvalSymlinks(dir)
+	assert.NoError(t, err)
+	t.Setenv("GH_HOST", "github.com")
+
+	tests := []struct {
+		name       string
+		opts       ExecOptions
+		arg        string
+		wantStdout string
+	}{
+		{
+			name:       "inherits environment",
+			arg:        "env",
+			wantStdout: "github.com",
+		},
+		{
+			name:       "environment overrides",
+			opts:       ExecOptions{Env: []string{"GH_HOST=example.com"}},
+			arg:        "env",
+			wantStdout: "example.com",
+		},
+		{
+			name:       "stdin",
+			opts:       ExecOptions{Stdin: strings.NewReader("hello")},
+			arg:        "stdin",
+			wantStdout: "hello",
+		},
+		{
+			name:       "working directory",
+			opts:       ExecOptions{Dir: dir},
+			arg:        "pwd",
+			wantStdout: dir,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := tt.opts
+			opts.Env = append([]string{"GH_WANT_HELPER_PROCESS=1"}, opts.Env...)
+			opts.Stdout = &stdout
+			err := run(context.TODO(), os.Args[0], opts,
+				[]string{"-test.run=TestHelperProcess", "--", "gh", tt.arg})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStdout, stdout.String())
+		})
+	}
+}
+
+func TestExecJSON(t *testing.T) {
+	t.Setenv("GH_PATH", os.Args[0])
+	helperArgs := []string{"-test.run=TestHelperProcessJSON", "--", "gh"}
+
+	tests := []struct {
+		name     string
+		opts     ExecJSONOptions
+		args     []string
+		wantArgs []string
+		wantErr  string
+	}{
+		{
+			name:     "fields",
+			opts:     ExecJSONOptions{Fields: []string{"number", "title"}},
+			args:     []string{"pr", "list"},
+			wantArgs: []string{"gh", "pr", "list", "--json", "number,title"},
+		},
+		{
+			name:     "jq and template",
+			opts:     ExecJSONOptions{Fields: []string{"number"}, JQ: ".[0]", Template: "{{json .}}"},
+			args:     []string{"pr", "list"},
+			wantArgs: []string{"gh", "pr", "list", "--json", "number", "--jq", ".[0]", "--template", "{{json .}}"},
+		},
+		{
+			name:    "no fields",
+			args:    []string{"pr", "list"},
+			wantErr: "at least one JSON field is required",
+		},
+		{
+			name:    "invalid output",
+			opts:    ExecJSONOptions{Fields: []string{"number"}},
+			args:    []string{"invalid"},
+			wantErr: "failed to decode gh output: invalid character 'o' in literal null (expecting 'u')",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.Env = []string{"GH_WANT_HELPER_PROCESS=1"}
+			var out struct {
+				Args []string `json:"args"`
+			}
+			err := ExecJSON(context.TODO(), opts, &out, append(helperArgs, tt.args...)...)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantArgs, out.Args)
+		})
+	}
 }
@@ -0,0 +1,172 @@
+package graphql
+
+// Document is a parsed GraphQL executable document.
+type Document struct {
+	Operations []*Operation
+	Fragments  []*Fragment
+}
+
+// Fragment returns the fragment with the given name, or nil.
+func (d *Document) Fragment(name string) *Fragment {
+	for _, f := range d.Fragments {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// OperationKind is the kind of a GraphQL operation.
+type OperationKind string
+
+const (
+	Query        OperationKind = "query"
+	Mutation     OperationKind = "mutation"
+	Subscription OperationKind = "subscription"
+)
+
+// Operation is a query, mutation, or subscription in a document.
+type Operation struct {
+	Kind         OperationKind
+	Name         string
+	Variables    []*VariableDefinition
+	Directives   []*Directive
+	SelectionSet []Selection
+	Source       string
+	Pos          Pos
+}
+
+// Fragment is a named fragment definition in a document.
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	Directives    []*Directive
+	SelectionSet  []Selection
+	Source        string
+	Pos           Pos
+}
+
+// VariableDefinition is a variable declared by an operation.
+type VariableDefinition struct {
+	Name         string
+	Type         *Type
+	DefaultValue *Value
+	Pos          Pos
+}
+
+// Type is a reference to a named, list, or non-null type.
+// Exactly one of Name or Elem is set.
+type Type struct {
+	Name    string
+	Elem    *Type
+	NonNull bool
+	Pos     Pos
+}
+
+// String returns the type in GraphQL syntax, such as "[String!]!".
+func (t *Type) String() string {
+	s := t.Name
+	if t.Elem != nil {
+		s = "[" + t.Elem.String() + "]"
+	}
+	if t.NonNull {
+		s += "!"
+	}
+	return s
+}
+
+// NamedType returns the name of the innermost named type.
+func (t *Type) NamedType() string {
+	if t.Elem != nil {
+		return t.Elem.NamedType()
+	}
+	return t.Name
+}
+
+// Selection is a Field, FragmentSpread, or InlineFragment.
+type Selection interface {
+	position() Pos
+}
+
+// Field is a field selection.
+type Field struct {
+	Alias        string
+	Name         string
+	Arguments    []*Argument
+	Directives   []*Directive
+	SelectionSet []Selection
+	Pos          Pos
+}
+
+// ResponseKey returns the key of the field in the response.
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// FragmentSpread is a spread of a named fragment.
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+	Pos        Pos
+}
+
+// InlineFragment is an inline fragment with an optional type condition.
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []*Directive
+	SelectionSet  []Selection
+	Pos           Pos
+}
+
+func (f *Field) position() Pos          { return f.Pos }
+func (f *FragmentSpread) position() Pos { return f.Pos }
+func (f *InlineFragment) position() Pos { return f.Pos }
+
+// Argument is an argument passed to a field or directive.
+type Argument struct {
+	Name  string
+	Value *Value
+	Pos   Pos
+}
+
+// Directive is a directive applied to a definition or selection.
+type Directive struct {
+	Name      string
+	Arguments []*Argument
+	Pos       Pos
+}
+
+// ValueKind is the kind of a literal value.
+type ValueKind int
+
+const (
+	VariableValue ValueKind = iota
+	IntValue
+	FloatValue
+	StringValue
+	BooleanValue
+	NullValue
+	EnumValue
+	ListValue
+	ObjectValue
+)
+
+// Value is a literal value or variable reference. Raw holds the
+// variable name, the scalar literal, or the enum value.
+type Value struct {
+	Kind   ValueKind
+	Raw    string
+	List   []*Value
+	Fields []*ObjectField
+	Pos    Pos
+}
+
+// ObjectField is a field of an input object literal.
+type ObjectField struct {
+	Name  string
+	Value *Value
+	Pos   Pos
+}
@@ -0,0 +1,458 @@
+package graphql
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var builtinScalars = map[string]string{
+	"Int":     "int",
+	"Float":   "float64",
+	"String":  "string",
+	"Boolean": "bool",
+	"ID":      "string",
+}
+
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "CPU": true, "CSS": true, "DNS": true, "GPG": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"OID": true, "SHA": true, "SQL": true, "SSH": true, "TLS": true, "UI": true,
+	"URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// GenerateOptions holds options for Generate.
+type GenerateOptions struct {
+	// Package is the name of the package of the generated code.
+	Package string
+
+	// Scalars maps custom scalar types to Go types, either predeclared or
+	// qualified by import path such as "time.Time" or "encoding/json.RawMessage".
+	// Custom scalars that are not mapped are represented as strings.
+	Scalars map[string]string
+}
+
+type generator struct {
+	schema  *Schema
+	doc     *Document
+	opts    GenerateOptions
+	imports map[string]string
+	decls   []string
+	names   map[string]bool
+	named   map[string]bool
+	queue   []string
+}
+
+// Generate returns Go source code with typed variables and responses for the
+// operations of a document, and a function for each operation that executes it
+// with api.GraphQLClient. The document is validated against the schema first,
+// and Errors is returned if it is invalid.
+func Generate(schema *Schema, doc *Document, opts GenerateOptions) ([]byte, error) {
+	if errs := Validate(schema, doc); len(errs) > 0 {
+		return nil, errs
+	}
+	if opts.Package == "" {
+		return nil, fmt.Errorf("package name is required")
+	}
+
+	g := &generator{
+		schema: schema,
+		doc:    doc,
+		opts:   opts,
+		imports: map[string]string{
+			"context":                         "context",
+			"github.com/cli/go-gh/v2/pkg/api": "api",
+		},
+		names: map[string]bool{},
+		named: map[string]bool{},
+	}
+
+	var errs Errors
+	ops := append([]*Operation{}, doc.Operations...)
+	sort.SliceStable(ops, func(i, j int) bool { return ops[i].Name < ops[j].Name })
+	for _, op := range ops {
+		if op.Name == "" {
+			errs = append(errs, &Error{Message: "operation must be named to generate code", Pos: op.Pos})
+			continue
+		}
+		if err := g.operation(op); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	for len(g.queue) > 0 {
+		name := g.queue[0]
+		g.queue = g.queue[1:]
+		g.namedType(schema.Types[name])
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by graphqlgen. DO NOT EDIT.\n\npackage %s\n\n", opts.Package)
+	paths := make([]string, 0, len(g.imports))
+	for p := range g.imports {
+		paths = append(paths, p)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if isStandardImport(paths[i]) != isStandardImport(paths[j]) {
+			return isStandardImport(paths[i])
+		}
+		return paths[i] < paths[j]
+	})
+	buf.WriteString("import (\n")
+	for i, p := range paths {
+		if i > 0 && isStandardImport(paths[i-1]) && !isStandardImport(p) {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "\t%q\n", p)
+	}
+	buf.WriteString(")\n")
+	for _, decl := range g.decls {
+		buf.WriteString("\n")
+		buf.WriteString(decl)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return src, nil
+}
+
+func (g *generator) operation(op *Operation) *Error {
+	name := goName(op.Name)
+	if g.names[name] {
+		return &Error{Message: fmt.Sprintf("operation %q conflicts with a generated type", op.Name), Pos: op.Pos}
+	}
+	g.names[name] = true
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// %sDocument is the %s %s.\n", name, op.Name, op.Kind)
+	fmt.Fprintf(&buf, "const %sDocument = %s\n", name, quote(g.document(op)))
+	g.decls = append(g.decls, buf.String())
+
+	responseName := g.typeName(name + "Response")
+	buf.Reset()
+	variablesName := ""
+	if len(op.Variables) > 0 {
+		variablesName = g.typeName(name + "Variables")
+		fmt.Fprintf(&buf, "// %s are the variables of the %s %s.\n", variablesName, op.Name, op.Kind)
+		fmt.Fprintf(&buf, "type %s struct {\n", variablesName)
+		for _, v := range op.Variables {
+			fmt.Fprintf(&buf, "\t%s %s `json:%q`\n", goName(v.Name), g.inputType(v.Type), v.Name)
+		}
+		buf.WriteString("}\n\n")
+		fmt.Fprintf(&buf, "func (v %s) variables() map[string]interface{} {\n", variablesName)
+		buf.WriteString("\tm := map[string]interface{}{}\n")
+		for _, v := range op.Variables {
+			field := goName(v.Name)
+			if v.Type.NonNull {
+				fmt.Fprintf(&buf, "\tm[%q] = v.%s\n", v.Name, field)
+				continue
+			}
+			fmt.Fprintf(&buf, "\tif v.%s != nil {\n\t\tm[%q] = v.%s\n\t}\n", field, v.Name, field)
+		}
+		buf.WriteString("\treturn m\n}\n")
+		g.decls = append(g.decls, buf.String())
+		buf.Reset()
+	}
+
+	fmt.Fprintf(&buf, "// %s executes the %s %s. If the response contains GraphQL errors,\n", name, op.Name, op.Kind)
+	buf.WriteString("// the partial response is returned with an *api.GraphQLError.\n")
+	params, variables := "", "nil"
+	if variablesName != "" {
+		params = ", variables " + variablesName
+		variables = "variables.variables()"
+	}
+	fmt.Fprintf(&buf, "func %s(ctx context.Context, client *api.GraphQLClient%s) (*%s, error) {\n", name, params, responseName)
+	fmt.Fprintf(&buf, "\tvar response %s\n", responseName)
+	fmt.Fprintf(&buf, "\terr := client.DoWithContext(ctx, %sDocument, %s, &response)\n", name, variables)
+	buf.WriteString("\treturn &response, err\n}\n")
+	g.decls = append(g.decls, buf.String())
+
+	root := g.schema.OperationType(op.Kind)
+	g.selectionStruct(responseName, name, fmt.Sprintf("%s is the response of the %s %s.", responseName, op.Name, op.Kind), root, op.SelectionSet)
+	return nil
+}
+
+// document returns the source of an operation followed
+// by the fragments that it uses.
+func (g *generator) document(op *Operation) string {
+	parts := []string{op.Source}
+	seen := map[string]bool{}
+	var add func([]Selection)
+	add = func(selections []Selection) {
+		for _, name := range fragmentSpreads(selections) {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if f := g.doc.Fragment(name); f != nil {
+				parts = append(parts, f.Source)
+				add(f.SelectionSet)
+			}
+		}
+	}
+	add(op.SelectionSet)
+	return strings.Join(parts, "\n")
+}
+
+type selectedField struct {
+	key        string
+	def        *FieldDefinition
+	selections []Selection
+}
+
+// selectionStruct generates a struct for the fields selected on parent. Structs
+// for nested selections are named by appending field names to prefix.
+func (g *generator) selectionStruct(name, prefix, doc string, parent *Definition, selections []Selection) {
+	var fields []*selectedField
+	byKey := map[string]*selectedField{}
+	var collect func(*Definition, []Selection, map[string]bool)
+	collect = func(def *Definition, selections []Selection, visited map[string]bool) {
+		for _, sel := range selections {
+			switch sel := sel.(type) {
+			case *Field:
+				key := sel.ResponseKey()
+				f, ok := byKey[key]
+				if !ok {
+					f = &selectedField{key: key, def: g.schema.Field(def, sel.Name)}
+					byKey[key] = f
+					fields = append(fields, f)
+				}
+				f.selections = append(f.selections, sel.SelectionSet...)
+			case *InlineFragment:
+				typeDef := def
+				if sel.TypeCondition != "" {
+					typeDef = g.schema.Types[sel.TypeCondition]
+				}
+				collect(typeDef, sel.SelectionSet, visited)
+			case *FragmentSpread:
+				if visited[sel.Name] {
+					continue
+				}
+				visited[sel.Name] = true
+				f := g.doc.Fragment(sel.Name)
+				collect(g.schema.Types[f.TypeCondition], f.SelectionSet, visited)
+			}
+		}
+	}
+	collect(parent, selections, map[string]bool{})
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// %s\n", doc)
+	fmt.Fprintf(&buf, "type %s struct {\n", name)
+	var nested []func()
+	for _, f := range fields {
+		f := f
+		if f.def.Description != "" {
+			writeComment(&buf, "\t", f.def.Description)
+		}
+		typeDef := g.schema.Types[f.def.Type.NamedType()]
+		var goType string
+		if typeDef.IsComposite() {
+			structName := g.typeName(prefix + goName(f.key))
+			goType = outputType(f.def.Type, structName, true)
+			selections := f.selections
+			nested = append(nested, func() {
+				g.selectionStruct(structName, structName, fmt.Sprintf("%s is the selection of %s on %s.", structName, f.key, typeDef.Name), typeDef, selections)
+			})
+		} else {
+			goType = outputType(f.def.Type, g.leafType(typeDef), false)
+		}
+		fmt.Fprintf(&buf, "\t%s %s `json:%q`\n", goName(f.key), goType, f.key)
+	}
+	buf.WriteString("}\n")
+	g.decls = append(g.decls, buf.String())
+
+	for _, fn := range nested {
+		fn()
+	}
+}
+
+// outputType returns the Go type for a field of type t whose named type is
+// represented by base. Nullable composite values are represented by pointers.
+func outputType(t *Type, base string, composite bool) string {
+	if t.Elem != nil {
+		return "[]" + outputType(t.Elem, base, composite)
+	}
+	if composite && !t.NonNull {
+		return "*" + base
+	}
+	return base
+}
+
+// inputType returns the Go type for an input value of type t.
+// Nullable named types are represented by pointers so they can be omitted.
+func (g *generator) inputType(t *Type) string {
+	if t.Elem != nil {
+		return "[]" + g.inputType(t.Elem)
+	}
+	def := g.schema.Types[t.Name]
+	base := g.leafType(def)
+	if def.Kind == InputObjectKind {
+		base = g.enqueue(def)
+	}
+	if !t.NonNull {
+		return "*" + base
+	}
+	return base
+}
+
+func (g *generator) leafType(def *Definition) string {
+	switch def.Kind {
+	case EnumKind:
+		return g.enqueue(def)
+	case ScalarKind:
+		if goType, ok := g.opts.Scalars[def.Name]; ok {
+			return g.qualify(goType)
+		}
+		if goType, ok := builtinScalars[def.Name]; ok {
+			return goType
+		}
+		return "string"
+	}
+	return goName(def.Name)
+}
+
+// enqueue schedules the generation of a named enum or input object type.
+func (g *generator) enqueue(def *Definition) string {
+	if !g.named[def.Name] {
+		g.named[def.Name] = true
+		g.queue = append(g.queue, def.Name)
+	}
+	return goName(def.Name)
+}
+
+func (g *generator) namedType(def *Definition) {
+	name := goName(def.Name)
+	g.names[name] = true
+
+	var buf bytes.Buffer
+	if def.Description != "" {
+		writeComment(&buf, "", name+" is the "+def.Name+" "+strings.ToLower(strings.ReplaceAll(string(def.Kind), "_", " "))+". "+def.Description)
+	} else {
+		fmt.Fprintf(&buf, "// %s is the %s %s.\n", name, def.Name, strings.ToLower(strings.ReplaceAll(string(def.Kind), "_", " ")))
+	}
+
+	switch def.Kind {
+	case EnumKind:
+		fmt.Fprintf(&buf, "type %s string\n\nconst (\n", name)
+		for _, v := range def.EnumValues {
+			if v.Description != "" {
+				writeComment(&buf, "\t", v.Description)
+			}
+			fmt.Fprintf(&buf, "\t%s%s %s = %q\n", name, goName(v.Name), name, v.Name)
+		}
+		buf.WriteString(")\n")
+	case InputObjectKind:
+		fmt.Fprintf(&buf, "type %s struct {\n", name)
+		for _, f := range def.InputFields {
+			if f.Description != "" {
+				writeComment(&buf, "\t", f.Description)
+			}
+			tag := f.Name
+			if !f.Type.NonNull {
+				tag += ",omitempty"
+			}
+			fmt.Fprintf(&buf, "\t%s %s `json:%q`\n", goName(f.Name), g.inputType(f.Type), tag)
+		}
+		buf.WriteString("}\n")
+	}
+	g.decls = append(g.decls, buf.String())
+}
+
+// typeName returns a unique name for a generated type.
+func (g *generator) typeName(name string) string {
+	unique := name
+	for i := 2; g.names[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	g.names[unique] = true
+	return unique
+}
+
+// qualify returns a Go type expression for a type that may be qualified by its
+// import path, such as "encoding/json.RawMessage", and adds its import.
+func (g *generator) qualify(goType string) string {
+	prefix := strings.TrimLeft(goType, "*[]")
+	i := strings.LastIndex(prefix, ".")
+	if i < 0 {
+		return goType
+	}
+	importPath := prefix[:i]
+	g.imports[importPath] = path.Base(importPath)
+	return goType[:len(goType)-len(prefix)] + path.Base(importPath) + prefix[i:]
+}
+
+func isStandardImport(importPath string) bool {
+	return !strings.Contains(strings.SplitN(importPath, "/", 2)[0], ".")
+}
+
+func writeComment(buf *bytes.Buffer, indent, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			fmt.Fprintf(buf, "%s//\n", indent)
+			continue
+		}
+		fmt.Fprintf(buf, "%s// %s\n", indent, line)
+	}
+}
+
+func quote(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
+// goName converts a GraphQL name such as "databaseId" or
+// "CLOSED_AS_DUPLICATE" to an exported Go name.
+func goName(name string) string {
+	var sb strings.Builder
+	for _, word := range splitWords(name) {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			sb.WriteString(upper)
+			continue
+		}
+		sb.WriteString(strings.ToUpper(word[:1]))
+		sb.WriteString(strings.ToLower(word[1:]))
+	}
+	s := sb.String()
+	if s == "" || !unicode.IsLetter(rune(s[0])) {
+		s = "X" + s
+	}
+	return s
+}
+
+// splitWords splits a name at underscores and changes of case.
+func splitWords(name string) []string {
+	var words []string
+	for _, part := range strings.Split(name, "_") {
+		start := 0
+		for i := 1; i < len(part); i++ {
+			prev, cur := rune(part[i-1]), rune(part[i])
+			var next rune
+			if i+1 < len(part) {
+				next = rune(part[i+1])
+			}
+			lowerToUpper := (unicode.IsLower(prev) || unicode.IsDigit(prev)) && unicode.IsUpper(cur)
+			acronymEnd := unicode.IsUpper(prev) && unicode.IsUpper(cur) && unicode.IsLower(next)
+			if lowerToUpper || acronymEnd {
+				words = append(words, part[start:i])
+				start = i
+			}
+		}
+		if start < len(part) {
+			words = append(words, part[start:])
+		}
+	}
+	return words
+}
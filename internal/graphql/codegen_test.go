@@ -0,0 +1,139 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testOperations = `query RepositoryInfo($owner: String!, $name: String!, $states: [IssueState!], $after: String) {
+  repository(owner: $owner, name: $name) {
+    id
+    name
+    url
+    databaseId
+    createdAt
+    owner { login }
+    issues(first: 10, states: $states, after: $after) {
+      nodes { ...IssueFields }
+    }
+  }
+}
+
+mutation AddStar($input: AddStarInput!) {
+  addStar(input: $input) {
+    starrable {
+      __typename
+      stargazerCount
+      ... on Repository { name }
+    }
+  }
+}
+
+fragment IssueFields on Issue {
+  number
+  title
+  state
+}
+`
+
+func TestGenerate(t *testing.T) {
+	schema := loadTestSchema(t)
+	doc, err := ParseQuery("operations.graphql", testOperations)
+	assert.NoError(t, err)
+
+	src, err := Generate(schema, doc, GenerateOptions{
+		Package: "queries",
+		Scalars: map[string]string{"DateTime": "time.Time"},
+	})
+	assert.NoError(t, err)
+	out := string(src)
+
+	assert.Contains(t, out, "// Code generated by graphqlgen. DO NOT EDIT.\n\npackage queries\n")
+	assert.Contains(t, out, "\t\"context\"\n\t\"time\"\n\n\t\"github.com/cli/go-gh/v2/pkg/api\"\n")
+
+	assert.Contains(t, out, "const RepositoryInfoDocument = `query RepositoryInfo(")
+	assert.Contains(t, out, "}\nfragment IssueFields on Issue {\n  number\n  title\n  state\n}`\n")
+	assert.Contains(t, out, `type RepositoryInfoVariables struct {
+	Owner  string       `+"`json:\"owner\"`"+`
+	Name   string       `+"`json:\"name\"`"+`
+	States []IssueState `+"`json:\"states\"`"+`
+	After  *string      `+"`json:\"after\"`"+`
+}`)
+	assert.Contains(t, out, `	if v.After != nil {
+		m["after"] = v.After
+	}`)
+	assert.Contains(t, out, "func RepositoryInfo(ctx context.Context, client *api.GraphQLClient, variables RepositoryInfoVariables) (*RepositoryInfoResponse, error) {")
+	assert.Contains(t, out, "err := client.DoWithContext(ctx, RepositoryInfoDocument, variables.variables(), &response)")
+	assert.Contains(t, out, `type RepositoryInfoResponse struct {
+	// Lookup a repository by owner and name.
+	Repository *RepositoryInfoRepository `+"`json:\"repository\"`")
+	assert.Contains(t, out, `type RepositoryInfoRepository struct {
+	ID   string `+"`json:\"id\"`"+`
+	Name string `+"`json:\"name\"`"+`
+	// The HTTP URL for this repository.
+	URL        string                         `+"`json:\"url\"`"+`
+	DatabaseID int                            `+"`json:\"databaseId\"`"+`
+	CreatedAt  time.Time                      `+"`json:\"createdAt\"`"+`
+	Owner      RepositoryInfoRepositoryOwner  `+"`json:\"owner\"`"+`
+	Issues     RepositoryInfoRepositoryIssues `+"`json:\"issues\"`"+`
+}`)
+	assert.Contains(t, out, "Nodes []*RepositoryInfoRepositoryIssuesNodes `json:\"nodes\"`")
+	assert.Contains(t, out, `type RepositoryInfoRepositoryIssuesNodes struct {
+	Number int        `+"`json:\"number\"`"+`
+	Title  string     `+"`json:\"title\"`"+`
+	State  IssueState `+"`json:\"state\"`"+`
+}`)
+
+	assert.Contains(t, out, `type AddStarAddStarStarrable struct {
+	Typename       string `+"`json:\"__typename\"`"+`
+	StargazerCount int    `+"`json:\"stargazerCount\"`"+`
+	Name           string `+"`json:\"name\"`"+`
+}`)
+	assert.Contains(t, out, `// AddStarInput is the AddStarInput input object.
+type AddStarInput struct {
+	// The Starrable ID to star.
+	StarrableID      string  `+"`json:\"starrableId\"`"+`
+	ClientMutationID *string `+"`json:\"clientMutationId,omitempty\"`"+`
+}`)
+	assert.Contains(t, out, `// IssueState is the IssueState enum. The possible states of an issue.
+type IssueState string
+
+const (
+	// An issue that is still open.
+	IssueStateOpen   IssueState = "OPEN"
+	IssueStateClosed IssueState = "CLOSED"
+)`)
+}
+
+func TestGenerateErrors(t *testing.T) {
+	schema := loadTestSchema(t)
+
+	doc, err := ParseQuery("invalid.graphql", `query Q { viewer { logn } }`)
+	assert.NoError(t, err)
+	_, err = Generate(schema, doc, GenerateOptions{Package: "queries"})
+	assert.EqualError(t, err, `invalid.graphql:1:20: field "logn" does not exist on type "User", did you mean "login"?`)
+
+	doc, err = ParseQuery("anonymous.graphql", `{ viewer { login } }`)
+	assert.NoError(t, err)
+	_, err = Generate(schema, doc, GenerateOptions{Package: "queries"})
+	assert.EqualError(t, err, `anonymous.graphql:1:1: operation must be named to generate code`)
+}
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"databaseId":          "DatabaseID",
+		"url":                 "URL",
+		"resourcePath":        "ResourcePath",
+		"HTMLUrl":             "HTMLURL",
+		"CLOSED_AS_DUPLICATE": "ClosedAsDuplicate",
+		"__typename":          "Typename",
+		"viewerCanReact":      "ViewerCanReact",
+		"oid":                 "OID",
+		"last7Days":           "Last7Days",
+		"2fa":                 "X2fa",
+	}
+	for input, want := range tests {
+		assert.Equal(t, want, goName(input), input)
+	}
+}
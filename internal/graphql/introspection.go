@@ -0,0 +1,274 @@
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// IntrospectionQuery is the query used to fetch a schema by introspection.
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives {
+      name
+      locations
+      args { ...InputValue }
+    }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  description
+  fields(includeDeprecated: true) {
+    name
+    description
+    args { ...InputValue }
+    type { ...TypeRef }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) {
+    name
+    description
+    isDeprecated
+    deprecationReason
+  }
+  possibleTypes { ...TypeRef }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+          ofType {
+            kind
+            name
+            ofType {
+              kind
+              name
+              ofType {
+                kind
+                name
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type introspectionSchema struct {
+	QueryType        *introspectionName       `json:"queryType"`
+	MutationType     *introspectionName       `json:"mutationType"`
+	SubscriptionType *introspectionName       `json:"subscriptionType"`
+	Types            []introspectionType      `json:"types"`
+	Directives       []introspectionDirective `json:"directives"`
+}
+
+type introspectionName struct {
+	Name string `json:"name"`
+}
+
+type introspectionType struct {
+	Kind          TypeKind                  `json:"kind"`
+	Name          string                    `json:"name"`
+	Description   string                    `json:"description"`
+	Fields        []introspectionField      `json:"fields"`
+	InputFields   []introspectionInputValue `json:"inputFields"`
+	Interfaces    []introspectionTypeRef    `json:"interfaces"`
+	EnumValues    []introspectionEnumValue  `json:"enumValues"`
+	PossibleTypes []introspectionTypeRef    `json:"possibleTypes"`
+}
+
+type introspectionField struct {
+	Name              string                    `json:"name"`
+	Description       string                    `json:"description"`
+	Args              []introspectionInputValue `json:"args"`
+	Type              introspectionTypeRef      `json:"type"`
+	IsDeprecated      bool                      `json:"isDeprecated"`
+	DeprecationReason string                    `json:"deprecationReason"`
+}
+
+type introspectionInputValue struct {
+	Name         string               `json:"name"`
+	Description  string               `json:"description"`
+	Type         introspectionTypeRef `json:"type"`
+	DefaultValue *string              `json:"defaultValue"`
+}
+
+type introspectionEnumValue struct {
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	IsDeprecated      bool   `json:"isDeprecated"`
+	DeprecationReason string `json:"deprecationReason"`
+}
+
+type introspectionTypeRef struct {
+	Kind   string                `json:"kind"`
+	Name   string                `json:"name"`
+	OfType *introspectionTypeRef `json:"ofType"`
+}
+
+type introspectionDirective struct {
+	Name      string                    `json:"name"`
+	Locations []string                  `json:"locations"`
+	Args      []introspectionInputValue `json:"args"`
+}
+
+// ParseIntrospection returns the schema described by the result of the
+// IntrospectionQuery. The result may be the data of the response, or
+// the full response with the data nested under a "data" key.
+func ParseIntrospection(data []byte) (*Schema, error) {
+	var result struct {
+		Schema *introspectionSchema `json:"__schema"`
+		Data   *struct {
+			Schema *introspectionSchema `json:"__schema"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	is := result.Schema
+	if is == nil && result.Data != nil {
+		is = result.Data.Schema
+	}
+	if is == nil {
+		return nil, errors.New("introspection result does not contain __schema")
+	}
+
+	schema := NewSchema()
+	if is.QueryType != nil {
+		schema.QueryType = is.QueryType.Name
+	}
+	if is.MutationType != nil {
+		schema.MutationType = is.MutationType.Name
+	}
+	if is.SubscriptionType != nil {
+		schema.SubscriptionType = is.SubscriptionType.Name
+	}
+
+	for _, t := range is.Types {
+		def := &Definition{Kind: t.Kind, Name: t.Name, Description: t.Description}
+		for _, f := range t.Fields {
+			args, err := inputValues(f.Args)
+			if err != nil {
+				return nil, err
+			}
+			def.Fields = append(def.Fields, &FieldDefinition{
+				Name:              f.Name,
+				Description:       f.Description,
+				Arguments:         args,
+				Type:              f.Type.toType(),
+				IsDeprecated:      f.IsDeprecated,
+				DeprecationReason: f.DeprecationReason,
+			})
+		}
+		inputFields, err := inputValues(t.InputFields)
+		if err != nil {
+			return nil, err
+		}
+		def.InputFields = inputFields
+		for _, i := range t.Interfaces {
+			def.Interfaces = append(def.Interfaces, i.Name)
+		}
+		for _, p := range t.PossibleTypes {
+			def.PossibleTypes = append(def.PossibleTypes, p.Name)
+		}
+		for _, v := range t.EnumValues {
+			def.EnumValues = append(def.EnumValues, &EnumValueDefinition{
+				Name:              v.Name,
+				Description:       v.Description,
+				IsDeprecated:      v.IsDeprecated,
+				DeprecationReason: v.DeprecationReason,
+			})
+		}
+		schema.Types[def.Name] = def
+	}
+
+	for _, d := range is.Directives {
+		args, err := inputValues(d.Args)
+		if err != nil {
+			return nil, err
+		}
+		schema.Directives[d.Name] = &DirectiveDefinition{Name: d.Name, Arguments: args, Locations: d.Locations}
+	}
+
+	return schema, nil
+}
+
+func inputValues(values []introspectionInputValue) ([]*InputValueDefinition, error) {
+	var defs []*InputValueDefinition
+	for _, v := range values {
+		def := &InputValueDefinition{
+			Name:        v.Name,
+			Description: v.Description,
+			Type:        v.Type.toType(),
+		}
+		if v.DefaultValue != nil {
+			value, err := parseConstValue(*v.DefaultValue)
+			if err != nil {
+				return nil, fmt.Errorf("invalid default value for %s: %w", v.Name, err)
+			}
+			def.DefaultValue = value
+		}
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+func (r introspectionTypeRef) toType() *Type {
+	switch r.Kind {
+	case "NON_NULL":
+		if r.OfType == nil {
+			return &Type{NonNull: true}
+		}
+		t := r.OfType.toType()
+		t.NonNull = true
+		return t
+	case "LIST":
+		if r.OfType == nil {
+			return &Type{Elem: &Type{}}
+		}
+		return &Type{Elem: r.OfType.toType()}
+	default:
+		return &Type{Name: r.Name}
+	}
+}
+
+func parseConstValue(src string) (value *Value, err error) {
+	p := &parser{lexer: newLexer("", src)}
+	defer p.recover(&err)
+	p.advance()
+	value = p.parseValue(true)
+	if p.tok.kind != tokenEOF {
+		p.unexpected()
+	}
+	return value, nil
+}
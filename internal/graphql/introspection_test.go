@@ -0,0 +1,127 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testIntrospection = `{
+  "data": {
+    "__schema": {
+      "queryType": {"name": "Query"},
+      "mutationType": null,
+      "subscriptionType": null,
+      "types": [
+        {
+          "kind": "OBJECT",
+          "name": "Query",
+          "description": "The root query type.",
+          "fields": [
+            {
+              "name": "repository",
+              "description": "Lookup a repository by owner and name.",
+              "args": [
+                {"name": "owner", "description": null, "type": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "SCALAR", "name": "String", "ofType": null}}, "defaultValue": null},
+                {"name": "name", "description": null, "type": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "SCALAR", "name": "String", "ofType": null}}, "defaultValue": null},
+                {"name": "followRenames", "description": null, "type": {"kind": "SCALAR", "name": "Boolean", "ofType": null}, "defaultValue": "true"}
+              ],
+              "type": {"kind": "OBJECT", "name": "Repository", "ofType": null},
+              "isDeprecated": false,
+              "deprecationReason": null
+            }
+          ],
+          "inputFields": null,
+          "interfaces": [],
+          "enumValues": null,
+          "possibleTypes": null
+        },
+        {
+          "kind": "OBJECT",
+          "name": "Repository",
+          "description": null,
+          "fields": [
+            {"name": "name", "description": null, "args": [], "type": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "SCALAR", "name": "String", "ofType": null}}, "isDeprecated": false, "deprecationReason": null},
+            {"name": "labels", "description": null, "args": [], "type": {"kind": "LIST", "name": null, "ofType": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "ENUM", "name": "Color", "ofType": null}}}, "isDeprecated": true, "deprecationReason": "Use topics."}
+          ],
+          "inputFields": null,
+          "interfaces": [{"kind": "INTERFACE", "name": "Node", "ofType": null}],
+          "enumValues": null,
+          "possibleTypes": null
+        },
+        {
+          "kind": "INTERFACE",
+          "name": "Node",
+          "description": null,
+          "fields": [],
+          "inputFields": null,
+          "interfaces": [],
+          "enumValues": null,
+          "possibleTypes": [{"kind": "OBJECT", "name": "Repository", "ofType": null}]
+        },
+        {
+          "kind": "ENUM",
+          "name": "Color",
+          "description": null,
+          "fields": null,
+          "inputFields": null,
+          "interfaces": null,
+          "enumValues": [{"name": "RED", "description": "Red.", "isDeprecated": false, "deprecationReason": null}],
+          "possibleTypes": null
+        }
+      ],
+      "directives": [
+        {"name": "preview", "locations": ["FIELD"], "args": [{"name": "toggledBy", "description": null, "type": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "SCALAR", "name": "String", "ofType": null}}, "defaultValue": null}]}
+      ]
+    }
+  }
+}`
+
+func TestParseIntrospection(t *testing.T) {
+	schema, err := ParseIntrospection([]byte(testIntrospection))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Query", schema.QueryType)
+	assert.Equal(t, "", schema.MutationType)
+
+	query := schema.Types["Query"]
+	assert.Equal(t, ObjectKind, query.Kind)
+	assert.Equal(t, "The root query type.", query.Description)
+	repository := query.Field("repository")
+	assert.Equal(t, "Repository", repository.Type.String())
+	assert.Equal(t, "String!", repository.Arguments[0].Type.String())
+	assert.Equal(t, BooleanValue, repository.Arguments[2].DefaultValue.Kind)
+
+	repo := schema.Types["Repository"]
+	assert.Equal(t, []string{"Node"}, repo.Interfaces)
+	assert.Equal(t, "[Color!]", repo.Field("labels").Type.String())
+	assert.True(t, repo.Field("labels").IsDeprecated)
+	assert.Equal(t, []string{"Repository"}, schema.PossibleTypes("Node"))
+	assert.Equal(t, "Red.", schema.Types["Color"].EnumValue("RED").Description)
+	assert.Equal(t, ScalarKind, schema.Types["String"].Kind)
+	assert.Equal(t, []string{"FIELD"}, schema.Directives["preview"].Locations)
+	assert.NotNil(t, schema.Directives["include"])
+
+	doc, err := ParseQuery("", `{ repository(owner: "cli", name: "cli") { name labels } }`)
+	assert.NoError(t, err)
+	assert.Empty(t, Validate(schema, doc))
+}
+
+func TestParseIntrospectionErrors(t *testing.T) {
+	_, err := ParseIntrospection([]byte(`{"data": {}}`))
+	assert.EqualError(t, err, "introspection result does not contain __schema")
+
+	_, err = ParseIntrospection([]byte(`{"__schema": {"types": [{"kind": "INPUT_OBJECT", "name": "I", "inputFields": [{"name": "a", "type": {"kind": "SCALAR", "name": "Int"}, "defaultValue": "$a"}]}]}}`))
+	assert.EqualError(t, err, "invalid default value for a: 1:1: unexpected variable in constant value")
+
+	_, err = ParseIntrospection([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestIntrospectionQueryIsValid(t *testing.T) {
+	doc, err := ParseQuery("", IntrospectionQuery)
+	assert.NoError(t, err)
+	assert.Len(t, doc.Operations, 1)
+	assert.Len(t, doc.Fragments, 3)
+	assert.Empty(t, Validate(loadTestSchema(t), doc))
+}
@@ -0,0 +1,345 @@
+// Package graphql is a parser and validator for GraphQL schemas and
+// executable documents, used to check and generate code for queries
+// without sending them to the GitHub API.
+package graphql
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+	tokenBlockString
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokenEOF:
+		return "end of input"
+	case tokenPunct:
+		return "punctuator"
+	case tokenName:
+		return "name"
+	case tokenInt:
+		return "int"
+	case tokenFloat:
+		return "float"
+	default:
+		return "string"
+	}
+}
+
+type token struct {
+	kind  tokenKind
+	value string
+	start int
+	end   int
+	pos   Pos
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return t.kind.String()
+	}
+	if t.kind == tokenString || t.kind == tokenBlockString {
+		return fmt.Sprintf("string %q", t.value)
+	}
+	return fmt.Sprintf("%q", t.value)
+}
+
+// Pos is a position in a GraphQL source.
+type Pos struct {
+	File   string
+	Line   int
+	Column int
+}
+
+func (p Pos) String() string {
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// Error is a syntax or validation error at a position in a GraphQL source.
+type Error struct {
+	Message string
+	Pos     Pos
+}
+
+// Allow Error to satisfy error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+// Errors is a list of errors found in a GraphQL source.
+type Errors []*Error
+
+// Allow Errors to satisfy error interface.
+func (errs Errors) Error() string {
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+type lexer struct {
+	file   string
+	src    string
+	offset int
+	line   int
+	column int
+}
+
+func newLexer(file, src string) *lexer {
+	return &lexer{file: file, src: src, line: 1, column: 1}
+}
+
+func (l *lexer) errorf(pos Pos, format string, args ...interface{}) *Error {
+	return &Error{Message: fmt.Sprintf(format, args...), Pos: pos}
+}
+
+func (l *lexer) pos() Pos {
+	return Pos{File: l.file, Line: l.line, Column: l.column}
+}
+
+func (l *lexer) advance(n int) {
+	for i := 0; i < n && l.offset < len(l.src); {
+		r, size := utf8.DecodeRuneInString(l.src[l.offset:])
+		l.offset += size
+		i += size
+		switch {
+		case r == '\n':
+			l.line++
+			l.column = 1
+		case r == '\r':
+			if l.offset >= len(l.src) || l.src[l.offset] != '\n' {
+				l.line++
+				l.column = 1
+			}
+		default:
+			l.column++
+		}
+	}
+}
+
+// skipIgnored skips whitespace, commas, comments, and the byte order mark.
+func (l *lexer) skipIgnored() {
+	for l.offset < len(l.src) {
+		switch c := l.src[l.offset]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.advance(1)
+		case c == '#':
+			for l.offset < len(l.src) && l.src[l.offset] != '\n' && l.src[l.offset] != '\r' {
+				l.advance(1)
+			}
+		case strings.HasPrefix(l.src[l.offset:], "\uFEFF"):
+			l.advance(len("\uFEFF"))
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	pos := l.pos()
+	start := l.offset
+	if l.offset >= len(l.src) {
+		return token{kind: tokenEOF, start: start, end: start, pos: pos}, nil
+	}
+
+	c := l.src[l.offset]
+	switch {
+	case strings.IndexByte("!$&():=@[]{}|", c) >= 0:
+		l.advance(1)
+		return token{kind: tokenPunct, value: string(c), start: start, end: l.offset, pos: pos}, nil
+	case strings.HasPrefix(l.src[l.offset:], "..."):
+		l.advance(3)
+		return token{kind: tokenPunct, value: "...", start: start, end: l.offset, pos: pos}, nil
+	case isNameStart(c):
+		for l.offset < len(l.src) && isNameContinue(l.src[l.offset]) {
+			l.advance(1)
+		}
+		return token{kind: tokenName, value: l.src[start:l.offset], start: start, end: l.offset, pos: pos}, nil
+	case c == '-' || isDigit(c):
+		return l.number(pos)
+	case strings.HasPrefix(l.src[l.offset:], `"""`):
+		return l.blockString(pos)
+	case c == '"':
+		return l.string(pos)
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.src[l.offset:])
+	return token{}, l.errorf(pos, "unexpected character %q", r)
+}
+
+func (l *lexer) number(pos Pos) (token, error) {
+	start := l.offset
+	kind := tokenInt
+	if l.src[l.offset] == '-' {
+		l.advance(1)
+	}
+	if err := l.digits(pos); err != nil {
+		return token{}, err
+	}
+	if l.offset < len(l.src) && l.src[l.offset] == '.' {
+		kind = tokenFloat
+		l.advance(1)
+		if err := l.digits(pos); err != nil {
+			return token{}, err
+		}
+	}
+	if l.offset < len(l.src) && (l.src[l.offset] == 'e' || l.src[l.offset] == 'E') {
+		kind = tokenFloat
+		l.advance(1)
+		if l.offset < len(l.src) && (l.src[l.offset] == '+' || l.src[l.offset] == '-') {
+			l.advance(1)
+		}
+		if err := l.digits(pos); err != nil {
+			return token{}, err
+		}
+	}
+	if l.offset < len(l.src) && (isNameStart(l.src[l.offset]) || l.src[l.offset] == '.') {
+		return token{}, l.errorf(pos, "invalid number %q", l.src[start:l.offset+1])
+	}
+	return token{kind: kind, value: l.src[start:l.offset], start: start, end: l.offset, pos: pos}, nil
+}
+
+func (l *lexer) digits(pos Pos) error {
+	if l.offset >= len(l.src) || !isDigit(l.src[l.offset]) {
+		return l.errorf(pos, "invalid number, expected digit")
+	}
+	for l.offset < len(l.src) && isDigit(l.src[l.offset]) {
+		l.advance(1)
+	}
+	return nil
+}
+
+func (l *lexer) string(pos Pos) (token, error) {
+	start := l.offset
+	l.advance(1)
+	var sb strings.Builder
+	for l.offset < len(l.src) {
+		c := l.src[l.offset]
+		switch {
+		case c == '"':
+			l.advance(1)
+			return token{kind: tokenString, value: sb.String(), start: start, end: l.offset, pos: pos}, nil
+		case c == '\n' || c == '\r':
+			return token{}, l.errorf(pos, "unterminated string")
+		case c == '\\':
+			if l.offset+1 >= len(l.src) {
+				return token{}, l.errorf(pos, "unterminated string")
+			}
+			esc := l.src[l.offset+1]
+			switch esc {
+			case '"', '\\', '/':
+				sb.WriteByte(esc)
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if l.offset+6 > len(l.src) {
+					return token{}, l.errorf(l.pos(), "invalid unicode escape sequence")
+				}
+				var r rune
+				if _, err := fmt.Sscanf(l.src[l.offset+2:l.offset+6], "%04x", &r); err != nil {
+					return token{}, l.errorf(l.pos(), "invalid unicode escape sequence")
+				}
+				sb.WriteRune(r)
+				l.advance(4)
+			default:
+				return token{}, l.errorf(l.pos(), "invalid escape sequence \\%c", esc)
+			}
+			l.advance(2)
+		default:
+			_, size := utf8.DecodeRuneInString(l.src[l.offset:])
+			sb.WriteString(l.src[l.offset : l.offset+size])
+			l.advance(size)
+		}
+	}
+	return token{}, l.errorf(pos, "unterminated string")
+}
+
+func (l *lexer) blockString(pos Pos) (token, error) {
+	start := l.offset
+	l.advance(3)
+	var sb strings.Builder
+	for l.offset < len(l.src) {
+		switch {
+		case strings.HasPrefix(l.src[l.offset:], `"""`):
+			l.advance(3)
+			return token{kind: tokenBlockString, value: blockStringValue(sb.String()), start: start, end: l.offset, pos: pos}, nil
+		case strings.HasPrefix(l.src[l.offset:], `\"""`):
+			sb.WriteString(`"""`)
+			l.advance(4)
+		default:
+			_, size := utf8.DecodeRuneInString(l.src[l.offset:])
+			sb.WriteString(l.src[l.offset : l.offset+size])
+			l.advance(size)
+		}
+	}
+	return token{}, l.errorf(pos, "unterminated block string")
+}
+
+// blockStringValue removes the common indentation and leading
+// and trailing blank lines from the raw value of a block string.
+func blockStringValue(raw string) string {
+	lines := strings.Split(strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(raw), "\n")
+	indent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if n := len(line) - len(trimmed); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	if indent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= indent {
+				lines[i] = lines[i][indent:]
+			} else {
+				lines[i] = ""
+			}
+		}
+	}
+	for len(lines) > 0 && strings.TrimLeft(lines[0], " \t") == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimLeft(lines[len(lines)-1], " \t") == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameContinue(c byte) bool {
+	return isNameStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,533 @@
+package graphql
+
+import (
+	"fmt"
+)
+
+type parser struct {
+	lexer   *lexer
+	tok     token
+	prevEnd int
+}
+
+// parseError is used to unwind the parser on the first syntax error.
+type parseError struct {
+	err *Error
+}
+
+// ParseQuery parses a GraphQL executable document containing operations and
+// fragments. The file name is used in the positions of errors and nodes.
+func ParseQuery(file, src string) (doc *Document, err error) {
+	p := &parser{lexer: newLexer(file, src)}
+	defer p.recover(&err)
+	p.advance()
+
+	doc = &Document{}
+	if p.tok.kind == tokenEOF {
+		p.errorf(p.tok.pos, "expected operation or fragment definition")
+	}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.peek("{"), p.peekName("query", "mutation", "subscription"):
+			doc.Operations = append(doc.Operations, p.parseOperation())
+		case p.peekName("fragment"):
+			doc.Fragments = append(doc.Fragments, p.parseFragment())
+		default:
+			p.unexpected()
+		}
+	}
+	return doc, nil
+}
+
+// ParseSchema parses a schema in the GraphQL schema definition language. The
+// file name is used in the positions of errors and definitions.
+func ParseSchema(file, src string) (schema *Schema, err error) {
+	p := &parser{lexer: newLexer(file, src)}
+	defer p.recover(&err)
+	p.advance()
+
+	schema = NewSchema()
+	var extensions []*Definition
+	for p.tok.kind != tokenEOF {
+		description := p.parseDescription()
+		pos := p.tok.pos
+		switch {
+		case p.peekName("schema"):
+			p.advance()
+			p.parseDirectives(true)
+			p.parseOperationTypes(schema)
+		case p.peekName("directive"):
+			d := p.parseDirectiveDefinition()
+			schema.Directives[d.Name] = d
+		case p.peekName("extend"):
+			p.advance()
+			if p.peekName("schema") {
+				p.advance()
+				p.parseDirectives(true)
+				if p.peek("{") {
+					p.parseOperationTypes(schema)
+				}
+				continue
+			}
+			extensions = append(extensions, p.parseTypeDefinition(""))
+		default:
+			def := p.parseTypeDefinition(description)
+			if existing, ok := schema.Types[def.Name]; ok && existing.Pos.Line != 0 {
+				p.errorf(pos, "type %s is already defined at %s", def.Name, existing.Pos)
+			}
+			schema.Types[def.Name] = def
+		}
+	}
+
+	for _, ext := range extensions {
+		def, ok := schema.Types[ext.Name]
+		if !ok {
+			return nil, &Error{Message: fmt.Sprintf("cannot extend undefined type %s", ext.Name), Pos: ext.Pos}
+		}
+		if def.Kind != ext.Kind {
+			return nil, &Error{Message: fmt.Sprintf("cannot extend %s %s as %s", def.Kind, def.Name, ext.Kind), Pos: ext.Pos}
+		}
+		def.Fields = append(def.Fields, ext.Fields...)
+		def.InputFields = append(def.InputFields, ext.InputFields...)
+		def.Interfaces = append(def.Interfaces, ext.Interfaces...)
+		def.EnumValues = append(def.EnumValues, ext.EnumValues...)
+		if def.Kind == UnionKind {
+			def.PossibleTypes = append(def.PossibleTypes, ext.PossibleTypes...)
+		}
+	}
+
+	schema.setDefaultOperationTypes()
+	schema.resolvePossibleTypes()
+	return schema, nil
+}
+
+func (p *parser) recover(err *error) {
+	if r := recover(); r != nil {
+		pe, ok := r.(parseError)
+		if !ok {
+			panic(r)
+		}
+		*err = pe.err
+	}
+}
+
+func (p *parser) errorf(pos Pos, format string, args ...interface{}) {
+	panic(parseError{&Error{Message: fmt.Sprintf(format, args...), Pos: pos}})
+}
+
+func (p *parser) unexpected() {
+	p.errorf(p.tok.pos, "unexpected %s", p.tok)
+}
+
+func (p *parser) advance() token {
+	prev := p.tok
+	p.prevEnd = prev.end
+	tok, err := p.lexer.next()
+	if err != nil {
+		panic(parseError{err.(*Error)})
+	}
+	p.tok = tok
+	return prev
+}
+
+func (p *parser) peek(punct string) bool {
+	return p.tok.kind == tokenPunct && p.tok.value == punct
+}
+
+func (p *parser) peekName(names ...string) bool {
+	if p.tok.kind != tokenName {
+		return false
+	}
+	for _, name := range names {
+		if p.tok.value == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) skip(punct string) bool {
+	if p.peek(punct) {
+		p.advance()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(punct string) token {
+	if !p.peek(punct) {
+		p.errorf(p.tok.pos, "expected %q, found %s", punct, p.tok)
+	}
+	return p.advance()
+}
+
+func (p *parser) expectKeyword(name string) token {
+	if !p.peekName(name) {
+		p.errorf(p.tok.pos, "expected %q, found %s", name, p.tok)
+	}
+	return p.advance()
+}
+
+func (p *parser) parseName() (string, Pos) {
+	if p.tok.kind != tokenName {
+		p.errorf(p.tok.pos, "expected name, found %s", p.tok)
+	}
+	tok := p.advance()
+	return tok.value, tok.pos
+}
+
+func (p *parser) parseOperation() *Operation {
+	start := p.tok
+	op := &Operation{Kind: Query, Pos: start.pos}
+	if !p.peek("{") {
+		op.Kind = OperationKind(p.advance().value)
+		if p.tok.kind == tokenName {
+			op.Name, _ = p.parseName()
+		}
+		op.Variables = p.parseVariableDefinitions()
+		op.Directives = p.parseDirectives(false)
+	}
+	op.SelectionSet = p.parseSelectionSet()
+	op.Source = p.lexer.src[start.start:p.prevEnd]
+	return op
+}
+
+func (p *parser) parseFragment() *Fragment {
+	start := p.expectKeyword("fragment")
+	f := &Fragment{Pos: start.pos}
+	var pos Pos
+	f.Name, pos = p.parseName()
+	if f.Name == "on" {
+		p.errorf(pos, "unexpected %q", f.Name)
+	}
+	p.expectKeyword("on")
+	f.TypeCondition, _ = p.parseName()
+	f.Directives = p.parseDirectives(false)
+	f.SelectionSet = p.parseSelectionSet()
+	f.Source = p.lexer.src[start.start:p.prevEnd]
+	return f
+}
+
+func (p *parser) parseVariableDefinitions() []*VariableDefinition {
+	if !p.skip("(") {
+		return nil
+	}
+	var defs []*VariableDefinition
+	for !p.skip(")") {
+		pos := p.expect("$").pos
+		v := &VariableDefinition{Pos: pos}
+		v.Name, _ = p.parseName()
+		p.expect(":")
+		v.Type = p.parseType()
+		if p.skip("=") {
+			v.DefaultValue = p.parseValue(true)
+		}
+		p.parseDirectives(true)
+		defs = append(defs, v)
+	}
+	return defs
+}
+
+func (p *parser) parseType() *Type {
+	pos := p.tok.pos
+	var t *Type
+	if p.skip("[") {
+		t = &Type{Elem: p.parseType(), Pos: pos}
+		p.expect("]")
+	} else {
+		name, _ := p.parseName()
+		t = &Type{Name: name, Pos: pos}
+	}
+	t.NonNull = p.skip("!")
+	return t
+}
+
+func (p *parser) parseDirectives(isConst bool) []*Directive {
+	var directives []*Directive
+	for p.peek("@") {
+		pos := p.advance().pos
+		d := &Directive{Pos: pos}
+		d.Name, _ = p.parseName()
+		d.Arguments = p.parseArguments(isConst)
+		directives = append(directives, d)
+	}
+	return directives
+}
+
+func (p *parser) parseArguments(isConst bool) []*Argument {
+	if !p.skip("(") {
+		return nil
+	}
+	var args []*Argument
+	for !p.skip(")") {
+		a := &Argument{}
+		a.Name, a.Pos = p.parseName()
+		p.expect(":")
+		a.Value = p.parseValue(isConst)
+		args = append(args, a)
+	}
+	return args
+}
+
+func (p *parser) parseSelectionSet() []Selection {
+	p.expect("{")
+	var selections []Selection
+	for !p.skip("}") {
+		selections = append(selections, p.parseSelection())
+	}
+	if len(selections) == 0 {
+		p.errorf(p.tok.pos, "selection set must not be empty")
+	}
+	return selections
+}
+
+func (p *parser) parseSelection() Selection {
+	if p.peek("...") {
+		pos := p.advance().pos
+		if p.tok.kind == tokenName && p.tok.value != "on" {
+			s := &FragmentSpread{Pos: pos}
+			s.Name, _ = p.parseName()
+			s.Directives = p.parseDirectives(false)
+			return s
+		}
+		f := &InlineFragment{Pos: pos}
+		if p.peekName("on") {
+			p.advance()
+			f.TypeCondition, _ = p.parseName()
+		}
+		f.Directives = p.parseDirectives(false)
+		f.SelectionSet = p.parseSelectionSet()
+		return f
+	}
+
+	f := &Field{}
+	f.Name, f.Pos = p.parseName()
+	if p.skip(":") {
+		f.Alias = f.Name
+		f.Name, _ = p.parseName()
+	}
+	f.Arguments = p.parseArguments(false)
+	f.Directives = p.parseDirectives(false)
+	if p.peek("{") {
+		f.SelectionSet = p.parseSelectionSet()
+	}
+	return f
+}
+
+func (p *parser) parseValue(isConst bool) *Value {
+	tok := p.tok
+	v := &Value{Raw: tok.value, Pos: tok.pos}
+	switch tok.kind {
+	case tokenInt:
+		v.Kind = IntValue
+	case tokenFloat:
+		v.Kind = FloatValue
+	case tokenString, tokenBlockString:
+		v.Kind = StringValue
+	case tokenName:
+		switch tok.value {
+		case "true", "false":
+			v.Kind = BooleanValue
+		case "null":
+			v.Kind = NullValue
+		default:
+			v.Kind = EnumValue
+		}
+	case tokenPunct:
+		switch tok.value {
+		case "$":
+			if isConst {
+				p.errorf(tok.pos, "unexpected variable in constant value")
+			}
+			p.advance()
+			v.Kind = VariableValue
+			v.Raw, _ = p.parseName()
+			return v
+		case "[":
+			p.advance()
+			v.Kind = ListValue
+			for !p.skip("]") {
+				v.List = append(v.List, p.parseValue(isConst))
+			}
+			return v
+		case "{":
+			p.advance()
+			v.Kind = ObjectValue
+			for !p.skip("}") {
+				f := &ObjectField{}
+				f.Name, f.Pos = p.parseName()
+				p.expect(":")
+				f.Value = p.parseValue(isConst)
+				v.Fields = append(v.Fields, f)
+			}
+			return v
+		default:
+			p.unexpected()
+		}
+	default:
+		p.unexpected()
+	}
+	p.advance()
+	return v
+}
+
+func (p *parser) parseDescription() string {
+	if p.tok.kind == tokenString || p.tok.kind == tokenBlockString {
+		return p.advance().value
+	}
+	return ""
+}
+
+func (p *parser) parseOperationTypes(schema *Schema) {
+	p.expect("{")
+	for !p.skip("}") {
+		operation, pos := p.parseName()
+		p.expect(":")
+		name, _ := p.parseName()
+		switch OperationKind(operation) {
+		case Query:
+			schema.QueryType = name
+		case Mutation:
+			schema.MutationType = name
+		case Subscription:
+			schema.SubscriptionType = name
+		default:
+			p.errorf(pos, "unknown operation type %q", operation)
+		}
+	}
+}
+
+func (p *parser) parseDirectiveDefinition() *DirectiveDefinition {
+	p.expectKeyword("directive")
+	p.expect("@")
+	d := &DirectiveDefinition{}
+	d.Name, _ = p.parseName()
+	d.Arguments = p.parseInputValueDefinitions("(", ")")
+	if p.peekName("repeatable") {
+		p.advance()
+	}
+	p.expectKeyword("on")
+	p.skip("|")
+	for {
+		location, _ := p.parseName()
+		d.Locations = append(d.Locations, location)
+		if !p.skip("|") {
+			break
+		}
+	}
+	return d
+}
+
+func (p *parser) parseTypeDefinition(description string) *Definition {
+	keyword := p.tok
+	p.advance()
+	def := &Definition{Description: description, Pos: keyword.pos}
+	def.Name, _ = p.parseName()
+
+	switch keyword.value {
+	case "scalar":
+		def.Kind = ScalarKind
+		p.parseDirectives(true)
+	case "type", "interface":
+		def.Kind = ObjectKind
+		if keyword.value == "interface" {
+			def.Kind = InterfaceKind
+		}
+		if p.peekName("implements") {
+			p.advance()
+			p.skip("&")
+			for {
+				name, _ := p.parseName()
+				def.Interfaces = append(def.Interfaces, name)
+				if !p.skip("&") && p.tok.kind != tokenName {
+					break
+				}
+			}
+		}
+		p.parseDirectives(true)
+		def.Fields = p.parseFieldDefinitions()
+	case "union":
+		def.Kind = UnionKind
+		p.parseDirectives(true)
+		if p.skip("=") {
+			p.skip("|")
+			for {
+				name, _ := p.parseName()
+				def.PossibleTypes = append(def.PossibleTypes, name)
+				if !p.skip("|") {
+					break
+				}
+			}
+		}
+	case "enum":
+		def.Kind = EnumKind
+		p.parseDirectives(true)
+		if p.skip("{") {
+			for !p.skip("}") {
+				v := &EnumValueDefinition{Description: p.parseDescription()}
+				v.Name, _ = p.parseName()
+				v.IsDeprecated, v.DeprecationReason = deprecation(p.parseDirectives(true))
+				def.EnumValues = append(def.EnumValues, v)
+			}
+		}
+	case "input":
+		def.Kind = InputObjectKind
+		p.parseDirectives(true)
+		def.InputFields = p.parseInputValueDefinitions("{", "}")
+	default:
+		p.errorf(keyword.pos, "unexpected %s, expected type definition", keyword)
+	}
+	return def
+}
+
+func (p *parser) parseFieldDefinitions() []*FieldDefinition {
+	if !p.skip("{") {
+		return nil
+	}
+	var fields []*FieldDefinition
+	for !p.skip("}") {
+		f := &FieldDefinition{Description: p.parseDescription()}
+		f.Name, _ = p.parseName()
+		f.Arguments = p.parseInputValueDefinitions("(", ")")
+		p.expect(":")
+		f.Type = p.parseType()
+		f.IsDeprecated, f.DeprecationReason = deprecation(p.parseDirectives(true))
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func (p *parser) parseInputValueDefinitions(open, close string) []*InputValueDefinition {
+	if !p.skip(open) {
+		return nil
+	}
+	var values []*InputValueDefinition
+	for !p.skip(close) {
+		v := &InputValueDefinition{Description: p.parseDescription()}
+		v.Name, _ = p.parseName()
+		p.expect(":")
+		v.Type = p.parseType()
+		if p.skip("=") {
+			v.DefaultValue = p.parseValue(true)
+		}
+		p.parseDirectives(true)
+		values = append(values, v)
+	}
+	return values
+}
+
+func deprecation(directives []*Directive) (bool, string) {
+	for _, d := range directives {
+		if d.Name != "deprecated" {
+			continue
+		}
+		reason := "No longer supported"
+		for _, a := range d.Arguments {
+			if a.Name == "reason" && a.Value.Kind == StringValue {
+				reason = a.Value.Raw
+			}
+		}
+		return true, reason
+	}
+	return false, ""
+}
@@ -0,0 +1,291 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSchema = `
+schema {
+  query: Query
+  mutation: Mutation
+}
+
+"""
+The root query type.
+"""
+type Query {
+  "Lookup a repository by owner and name."
+  repository(owner: String!, name: String!, followRenames: Boolean = true): Repository
+  node(id: ID!): Node
+  search(query: String!, type: SearchType!, first: Int): [SearchResult!]!
+  viewer: User!
+}
+
+type Mutation {
+  addStar(input: AddStarInput!): AddStarPayload
+}
+
+interface Node {
+  id: ID!
+}
+
+type Repository implements Node & Starrable {
+  id: ID!
+  name: String!
+  "The HTTP URL for this repository."
+  url: URI!
+  databaseId: Int
+  owner: User!
+  stargazerCount: Int!
+  createdAt: DateTime!
+  issues(first: Int, states: [IssueState!], after: String): IssueConnection!
+  isFork: Boolean! @deprecated(reason: "Use parent instead.")
+}
+
+interface Starrable {
+  id: ID!
+  stargazerCount: Int!
+}
+
+type User implements Node {
+  id: ID!
+  login: String!
+}
+
+type Issue implements Node {
+  id: ID!
+  number: Int!
+  title: String!
+  state: IssueState!
+}
+
+type IssueConnection {
+  totalCount: Int!
+  nodes: [Issue]
+}
+
+"""
+The possible states of an issue.
+"""
+enum IssueState {
+  "An issue that is still open."
+  OPEN
+  CLOSED
+}
+
+enum SearchType {
+  ISSUE
+  REPOSITORY
+}
+
+union SearchResult = Issue | Repository
+
+input AddStarInput {
+  "The Starrable ID to star."
+  starrableId: ID!
+  clientMutationId: String
+}
+
+type AddStarPayload {
+  clientMutationId: String
+  starrable: Starrable
+}
+
+scalar DateTime
+scalar URI
+
+directive @preview(toggledBy: String!) on FIELD | FRAGMENT_SPREAD
+`
+
+func loadTestSchema(t *testing.T) *Schema {
+	t.Helper()
+	schema, err := ParseSchema("schema.graphql", testSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func TestParseSchema(t *testing.T) {
+	schema := loadTestSchema(t)
+
+	assert.Equal(t, "Query", schema.QueryType)
+	assert.Equal(t, "Mutation", schema.MutationType)
+	assert.Equal(t, "", schema.SubscriptionType)
+
+	query := schema.Types["Query"]
+	assert.Equal(t, ObjectKind, query.Kind)
+	assert.Equal(t, "The root query type.", query.Description)
+	repository := query.Field("repository")
+	assert.Equal(t, "Lookup a repository by owner and name.", repository.Description)
+	assert.Equal(t, "Repository", repository.Type.String())
+	assert.Len(t, repository.Arguments, 3)
+	assert.Equal(t, "String!", repository.Arguments[0].Type.String())
+	assert.Equal(t, "true", repository.Arguments[2].DefaultValue.Raw)
+	assert.Equal(t, "[SearchResult!]!", query.Field("search").Type.String())
+
+	repo := schema.Types["Repository"]
+	assert.Equal(t, []string{"Node", "Starrable"}, repo.Interfaces)
+	assert.True(t, repo.Field("isFork").IsDeprecated)
+	assert.Equal(t, "Use parent instead.", repo.Field("isFork").DeprecationReason)
+	assert.NotNil(t, repo.Field("__typename"))
+	assert.Equal(t, Pos{File: "schema.graphql", Line: 26, Column: 1}, repo.Pos)
+
+	assert.Equal(t, []string{"Issue", "Repository", "User"}, schema.PossibleTypes("Node"))
+	assert.Equal(t, []string{"Issue", "Repository"}, schema.PossibleTypes("SearchResult"))
+	assert.Equal(t, []string{"Repository"}, schema.PossibleTypes("Repository"))
+
+	state := schema.Types["IssueState"]
+	assert.Equal(t, EnumKind, state.Kind)
+	assert.Equal(t, "An issue that is still open.", state.EnumValue("OPEN").Description)
+
+	input := schema.Types["AddStarInput"]
+	assert.Equal(t, InputObjectKind, input.Kind)
+	assert.Equal(t, "ID!", input.InputField("starrableId").Type.String())
+
+	assert.Equal(t, ScalarKind, schema.Types["DateTime"].Kind)
+	assert.Equal(t, ScalarKind, schema.Types["String"].Kind)
+	assert.Equal(t, []string{"FIELD", "FRAGMENT_SPREAD"}, schema.Directives["preview"].Locations)
+}
+
+func TestParseSchemaExtensions(t *testing.T) {
+	schema, err := ParseSchema("", `
+type Query { a: String }
+extend type Query { b: Int }
+enum Color { RED }
+extend enum Color { BLUE }
+`)
+	assert.NoError(t, err)
+	assert.NotNil(t, schema.Types["Query"].Field("b"))
+	assert.NotNil(t, schema.Types["Color"].EnumValue("BLUE"))
+	assert.Equal(t, "Query", schema.QueryType)
+
+	_, err = ParseSchema("", `extend type Missing { a: String }`)
+	assert.EqualError(t, err, "1:8: cannot extend undefined type Missing")
+}
+
+func TestParseQuery(t *testing.T) {
+	src := `# Fetches a repository.
+query RepositoryInfo($owner: String!, $name: String! = "cli", $states: [IssueState!]) {
+  repository(owner: $owner, name: $name) {
+    repoName: name
+    issues(first: 10, states: $states) @include(if: true) {
+      nodes { ...IssueFields }
+    }
+    ... on Starrable { stargazerCount }
+  }
+}
+
+fragment IssueFields on Issue {
+  title
+  state
+}
+`
+	doc, err := ParseQuery("query.graphql", src)
+	assert.NoError(t, err)
+	assert.Len(t, doc.Operations, 1)
+	assert.Len(t, doc.Fragments, 1)
+
+	op := doc.Operations[0]
+	assert.Equal(t, Query, op.Kind)
+	assert.Equal(t, "RepositoryInfo", op.Name)
+	assert.Equal(t, Pos{File: "query.graphql", Line: 2, Column: 1}, op.Pos)
+	assert.True(t, len(op.Source) > 0 && op.Source[:5] == "query" && op.Source[len(op.Source)-1] == '}')
+	assert.Len(t, op.Variables, 3)
+	assert.Equal(t, "String!", op.Variables[1].Type.String())
+	assert.Equal(t, StringValue, op.Variables[1].DefaultValue.Kind)
+	assert.Equal(t, "[IssueState!]", op.Variables[2].Type.String())
+
+	repository := op.SelectionSet[0].(*Field)
+	assert.Equal(t, "repository", repository.Name)
+	assert.Equal(t, VariableValue, repository.Arguments[0].Value.Kind)
+	assert.Equal(t, "owner", repository.Arguments[0].Value.Raw)
+
+	name := repository.SelectionSet[0].(*Field)
+	assert.Equal(t, "repoName", name.ResponseKey())
+	assert.Equal(t, "name", name.Name)
+
+	issues := repository.SelectionSet[1].(*Field)
+	assert.Equal(t, "include", issues.Directives[0].Name)
+	spread := issues.SelectionSet[0].(*Field).SelectionSet[0].(*FragmentSpread)
+	assert.Equal(t, "IssueFields", spread.Name)
+
+	inline := repository.SelectionSet[2].(*InlineFragment)
+	assert.Equal(t, "Starrable", inline.TypeCondition)
+
+	fragment := doc.Fragment("IssueFields")
+	assert.Equal(t, "Issue", fragment.TypeCondition)
+	assert.Equal(t, "fragment IssueFields on Issue {\n  title\n  state\n}", fragment.Source)
+}
+
+func TestParseQueryValues(t *testing.T) {
+	doc, err := ParseQuery("", `{ f(a: -1.5e3, b: [1, "two", ENUM], c: {d: null, e: false}, s: """
+    block
+      string
+  """) }`)
+	assert.NoError(t, err)
+	args := doc.Operations[0].SelectionSet[0].(*Field).Arguments
+	assert.Equal(t, FloatValue, args[0].Value.Kind)
+	assert.Equal(t, "-1.5e3", args[0].Value.Raw)
+	assert.Equal(t, `[1, "two", ENUM]`, printValue(args[1].Value))
+	assert.Equal(t, `{d: null, e: false}`, printValue(args[2].Value))
+	assert.Equal(t, "block\n  string", args[3].Value.Raw)
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		wantErr string
+	}{
+		{
+			name:    "empty document",
+			src:     "  ",
+			wantErr: "1:3: expected operation or fragment definition",
+		},
+		{
+			name:    "unclosed selection set",
+			src:     "query {\n  viewer {\n    login\n",
+			wantErr: "4:1: expected name, found end of input",
+		},
+		{
+			name:    "missing colon in variable definition",
+			src:     "query Q($a String) { viewer }",
+			wantErr: `1:12: expected ":", found "String"`,
+		},
+		{
+			name:    "unexpected character",
+			src:     "{ viewer; }",
+			wantErr: `1:9: unexpected character ';'`,
+		},
+		{
+			name:    "unterminated string",
+			src:     `{ f(a: "abc) }`,
+			wantErr: `1:8: unterminated string`,
+		},
+		{
+			name:    "invalid number",
+			src:     `{ f(a: 1.) }`,
+			wantErr: `1:8: invalid number, expected digit`,
+		},
+		{
+			name:    "variable in constant",
+			src:     `query Q($a: Int = $b) { viewer }`,
+			wantErr: `1:19: unexpected variable in constant value`,
+		},
+		{
+			name:    "empty selection set",
+			src:     `{ viewer { } }`,
+			wantErr: `1:14: selection set must not be empty`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseQuery("", tt.src)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Request is the JSON body of a request to the GraphQL API.
+type Request struct {
+	Query         string          `json:"query"`
+	OperationName string          `json:"operationName,omitempty"`
+	Variables     json.RawMessage `json:"variables,omitempty"`
+}
+
+// ParseRequest decodes the JSON body of a GraphQL request.
+// Returns an error if body is not a JSON object with a query.
+func ParseRequest(body []byte) (*Request, error) {
+	var r Request
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, err
+	}
+	if r.Query == "" {
+		return nil, errors.New("missing GraphQL query")
+	}
+	return &r, nil
+}
+
+// Operation returns the kind and name of the operation executed by the
+// request. The operation is selected by OperationName if it is set, and
+// otherwise must be the only operation in the query. Only the top-level
+// definitions of the query are scanned, so it is cheap enough to call for
+// every request but does not report syntax errors inside selection sets.
+// If the query cannot be scanned or the operation is not found, ok is false
+// and name is OperationName.
+func (r *Request) Operation() (kind OperationKind, name string, ok bool) {
+	type operation struct {
+		kind OperationKind
+		name string
+	}
+	var ops []operation
+
+	l := newLexer("query", r.Query)
+	braces, parens := 0, 0
+	atDefinition := true
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return "", r.OperationName, false
+		}
+		if tok.kind == tokenEOF {
+			break
+		}
+		if tok.kind == tokenPunct {
+			switch tok.value {
+			case "(":
+				parens++
+			case ")":
+				parens--
+			case "{":
+				if parens > 0 {
+					break
+				}
+				if braces == 0 && atDefinition {
+					// A selection set on its own is an anonymous query.
+					ops = append(ops, operation{kind: Query})
+				}
+				braces++
+				atDefinition = false
+			case "}":
+				if parens > 0 {
+					break
+				}
+				braces--
+				atDefinition = braces == 0
+			}
+			continue
+		}
+		if tok.kind != tokenName || braces > 0 || parens > 0 || !atDefinition {
+			continue
+		}
+		atDefinition = false
+		switch k := OperationKind(tok.value); k {
+		case Query, Mutation, Subscription:
+			op := operation{kind: k}
+			next, err := l.next()
+			if err != nil {
+				return "", r.OperationName, false
+			}
+			switch {
+			case next.kind == tokenName:
+				op.name = next.value
+			case next.kind == tokenPunct && next.value == "{":
+				braces++
+			case next.kind == tokenPunct && next.value == "(":
+				parens++
+			}
+			ops = append(ops, op)
+		}
+	}
+	if braces != 0 || parens != 0 {
+		return "", r.OperationName, false
+	}
+
+	for _, op := range ops {
+		if op.name == r.OperationName || (r.OperationName == "" && len(ops) == 1) {
+			return op.kind, op.name, true
+		}
+	}
+	return "", r.OperationName, false
+}
@@ -0,0 +1,101 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestOperation(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantErr  bool
+		wantKind OperationKind
+		wantName string
+		wantOK   bool
+	}{
+		{
+			name:     "named query",
+			body:     `{"query": "query Viewer { viewer { login } }"}`,
+			wantKind: "query",
+			wantName: "Viewer",
+			wantOK:   true,
+		},
+		{
+			name:     "anonymous query",
+			body:     `{"query": "{ viewer { login } }"}`,
+			wantKind: "query",
+			wantOK:   true,
+		},
+		{
+			name:     "mutation",
+			body:     `{"query": "mutation AddComment($input: AddCommentInput!) { addComment(input: $input) { clientMutationId } }", "variables": {"input": {}}}`,
+			wantKind: "mutation",
+			wantName: "AddComment",
+			wantOK:   true,
+		},
+		{
+			name:     "selected by operation name",
+			body:     `{"query": "query A { viewer { login } } mutation B { addStar { clientMutationId } }", "operationName": "B"}`,
+			wantKind: "mutation",
+			wantName: "B",
+			wantOK:   true,
+		},
+		{
+			name:     "ambiguous operation",
+			body:     `{"query": "query A { viewer { login } } mutation B { addStar { clientMutationId } }"}`,
+			wantOK:   false,
+			wantName: "",
+		},
+		{
+			name:     "unknown operation name",
+			body:     `{"query": "query A { viewer { login } }", "operationName": "C"}`,
+			wantName: "C",
+		},
+		{
+			name:     "fragment and directive arguments",
+			body:     `{"query": "fragment F on User { login } query Q($a: In = {x: 1}) @d(v: {y: 2}) { viewer { ...F } }"}`,
+			wantKind: "query",
+			wantName: "Q",
+			wantOK:   true,
+		},
+		{
+			name:     "braces in strings",
+			body:     `{"query": "mutation M { add(body: \"} query X {\") { id } }"}`,
+			wantKind: "mutation",
+			wantName: "M",
+			wantOK:   true,
+		},
+		{
+			name:     "unbalanced query",
+			body:     `{"query": "mutation {"}`,
+			wantKind: "",
+		},
+		{
+			name:    "missing query",
+			body:    `{"variables": {}}`,
+			wantErr: true,
+		},
+		{
+			name:    "not JSON",
+			body:    `query { viewer { login } }`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := ParseRequest([]byte(tt.body))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			kind, name, ok := req.Operation()
+			assert.Equal(t, tt.wantKind, kind)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
@@ -0,0 +1,357 @@
+package graphql
+
+import "sort"
+
+// TypeKind is the kind of a named type in a schema. The values
+// match the kinds reported by schema introspection.
+type TypeKind string
+
+const (
+	ScalarKind      TypeKind = "SCALAR"
+	ObjectKind      TypeKind = "OBJECT"
+	InterfaceKind   TypeKind = "INTERFACE"
+	UnionKind       TypeKind = "UNION"
+	EnumKind        TypeKind = "ENUM"
+	InputObjectKind TypeKind = "INPUT_OBJECT"
+)
+
+// Schema is a GraphQL schema.
+type Schema struct {
+	Types            map[string]*Definition
+	Directives       map[string]*DirectiveDefinition
+	QueryType        string
+	MutationType     string
+	SubscriptionType string
+}
+
+// Definition is a named type defined in a schema.
+type Definition struct {
+	Kind          TypeKind
+	Name          string
+	Description   string
+	Fields        []*FieldDefinition
+	InputFields   []*InputValueDefinition
+	Interfaces    []string
+	PossibleTypes []string
+	EnumValues    []*EnumValueDefinition
+	Pos           Pos
+}
+
+// FieldDefinition is a field of an object or interface type.
+type FieldDefinition struct {
+	Name              string
+	Description       string
+	Arguments         []*InputValueDefinition
+	Type              *Type
+	DeprecationReason string
+	IsDeprecated      bool
+}
+
+// InputValueDefinition is an argument or a field of an input object type.
+type InputValueDefinition struct {
+	Name         string
+	Description  string
+	Type         *Type
+	DefaultValue *Value
+}
+
+// EnumValueDefinition is a value of an enum type.
+type EnumValueDefinition struct {
+	Name              string
+	Description       string
+	DeprecationReason string
+	IsDeprecated      bool
+}
+
+// DirectiveDefinition is a directive defined in a schema.
+type DirectiveDefinition struct {
+	Name      string
+	Arguments []*InputValueDefinition
+	Locations []string
+}
+
+var typenameField = &FieldDefinition{
+	Name: "__typename",
+	Type: &Type{Name: "String", NonNull: true},
+}
+
+// schemaField and typeField are the meta fields of the query root type.
+var (
+	schemaField = &FieldDefinition{
+		Name: "__schema",
+		Type: &Type{Name: "__Schema", NonNull: true},
+	}
+	typeField = &FieldDefinition{
+		Name:      "__type",
+		Arguments: []*InputValueDefinition{{Name: "name", Type: &Type{Name: "String", NonNull: true}}},
+		Type:      &Type{Name: "__Type"},
+	}
+)
+
+// introspectionTypes are the types of the introspection system,
+// which are part of every schema.
+const introspectionTypes = `
+type __Schema {
+  description: String
+  types: [__Type!]!
+  queryType: __Type!
+  mutationType: __Type
+  subscriptionType: __Type
+  directives: [__Directive!]!
+}
+
+type __Type {
+  kind: __TypeKind!
+  name: String
+  description: String
+  specifiedByURL: String
+  fields(includeDeprecated: Boolean = false): [__Field!]
+  interfaces: [__Type!]
+  possibleTypes: [__Type!]
+  enumValues(includeDeprecated: Boolean = false): [__EnumValue!]
+  inputFields(includeDeprecated: Boolean = false): [__InputValue!]
+  ofType: __Type
+}
+
+enum __TypeKind {
+  SCALAR
+  OBJECT
+  INTERFACE
+  UNION
+  ENUM
+  INPUT_OBJECT
+  LIST
+  NON_NULL
+}
+
+type __Field {
+  name: String!
+  description: String
+  args(includeDeprecated: Boolean = false): [__InputValue!]!
+  type: __Type!
+  isDeprecated: Boolean!
+  deprecationReason: String
+}
+
+type __InputValue {
+  name: String!
+  description: String
+  type: __Type!
+  defaultValue: String
+  isDeprecated: Boolean!
+  deprecationReason: String
+}
+
+type __EnumValue {
+  name: String!
+  description: String
+  isDeprecated: Boolean!
+  deprecationReason: String
+}
+
+type __Directive {
+  name: String!
+  description: String
+  locations: [__DirectiveLocation!]!
+  args(includeDeprecated: Boolean = false): [__InputValue!]!
+  isRepeatable: Boolean!
+}
+
+enum __DirectiveLocation {
+  QUERY
+  MUTATION
+  SUBSCRIPTION
+  FIELD
+  FRAGMENT_DEFINITION
+  FRAGMENT_SPREAD
+  INLINE_FRAGMENT
+  VARIABLE_DEFINITION
+  SCHEMA
+  SCALAR
+  OBJECT
+  FIELD_DEFINITION
+  ARGUMENT_DEFINITION
+  INTERFACE
+  UNION
+  ENUM
+  ENUM_VALUE
+  INPUT_OBJECT
+  INPUT_FIELD_DEFINITION
+}
+`
+
+// NewSchema returns a schema containing only the built-in scalars, directives
+// and introspection types.
+func NewSchema() *Schema {
+	s := &Schema{
+		Types:      map[string]*Definition{},
+		Directives: map[string]*DirectiveDefinition{},
+	}
+	for _, name := range []string{"Int", "Float", "String", "Boolean", "ID"} {
+		s.Types[name] = &Definition{Kind: ScalarKind, Name: name}
+	}
+	ifArgument := []*InputValueDefinition{{Name: "if", Type: &Type{Name: "Boolean", NonNull: true}}}
+	s.Directives["include"] = &DirectiveDefinition{
+		Name:      "include",
+		Arguments: ifArgument,
+		Locations: []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+	}
+	s.Directives["skip"] = &DirectiveDefinition{
+		Name:      "skip",
+		Arguments: ifArgument,
+		Locations: []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+	}
+	s.Directives["deprecated"] = &DirectiveDefinition{
+		Name:      "deprecated",
+		Arguments: []*InputValueDefinition{{Name: "reason", Type: &Type{Name: "String"}}},
+		Locations: []string{"FIELD_DEFINITION", "ARGUMENT_DEFINITION", "INPUT_FIELD_DEFINITION", "ENUM_VALUE"},
+	}
+	s.Directives["specifiedBy"] = &DirectiveDefinition{
+		Name:      "specifiedBy",
+		Arguments: []*InputValueDefinition{{Name: "url", Type: &Type{Name: "String", NonNull: true}}},
+		Locations: []string{"SCALAR"},
+	}
+
+	p := &parser{lexer: newLexer("introspection.graphql", introspectionTypes)}
+	p.advance()
+	for p.tok.kind != tokenEOF {
+		def := p.parseTypeDefinition("")
+		// Built-in types have no position so that schemas may redefine them.
+		def.Pos = Pos{}
+		s.Types[def.Name] = def
+	}
+	return s
+}
+
+// Field returns the field with the given name of the parent type, including
+// the __schema and __type meta fields of the query root type, or nil.
+func (s *Schema) Field(parent *Definition, name string) *FieldDefinition {
+	if parent.Name == s.QueryType {
+		switch name {
+		case schemaField.Name:
+			return schemaField
+		case typeField.Name:
+			return typeField
+		}
+	}
+	return parent.Field(name)
+}
+
+// OperationType returns the root type for the kind of operation, or nil
+// if the schema does not support the kind of operation.
+func (s *Schema) OperationType(kind OperationKind) *Definition {
+	switch kind {
+	case Query:
+		return s.Types[s.QueryType]
+	case Mutation:
+		return s.Types[s.MutationType]
+	case Subscription:
+		return s.Types[s.SubscriptionType]
+	}
+	return nil
+}
+
+// PossibleTypes returns the names of the object types that a value of the
+// named type can have.
+func (s *Schema) PossibleTypes(name string) []string {
+	def := s.Types[name]
+	if def == nil {
+		return nil
+	}
+	switch def.Kind {
+	case ObjectKind:
+		return []string{name}
+	case UnionKind, InterfaceKind:
+		return def.PossibleTypes
+	}
+	return nil
+}
+
+// Field returns the field with the given name, including the __typename meta
+// field, or nil if the type has no such field.
+func (d *Definition) Field(name string) *FieldDefinition {
+	if name == typenameField.Name && d.IsComposite() {
+		return typenameField
+	}
+	for _, f := range d.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// InputField returns the input field with the given name, or nil.
+func (d *Definition) InputField(name string) *InputValueDefinition {
+	for _, f := range d.InputFields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// EnumValue returns the enum value with the given name, or nil.
+func (d *Definition) EnumValue(name string) *EnumValueDefinition {
+	for _, v := range d.EnumValues {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// IsComposite reports whether the type is an object, interface, or union type.
+func (d *Definition) IsComposite() bool {
+	return d.Kind == ObjectKind || d.Kind == InterfaceKind || d.Kind == UnionKind
+}
+
+// IsLeaf reports whether the type is a scalar or enum type.
+func (d *Definition) IsLeaf() bool {
+	return d.Kind == ScalarKind || d.Kind == EnumKind
+}
+
+// IsInput reports whether the type is a scalar, enum, or input object type.
+func (d *Definition) IsInput() bool {
+	return d.IsLeaf() || d.Kind == InputObjectKind
+}
+
+// resolvePossibleTypes records the object types implementing each interface.
+func (s *Schema) resolvePossibleTypes() {
+	implementations := map[string][]string{}
+	for _, def := range s.Types {
+		if def.Kind != ObjectKind {
+			continue
+		}
+		for _, iface := range def.Interfaces {
+			implementations[iface] = append(implementations[iface], def.Name)
+		}
+	}
+	for name, types := range implementations {
+		if def := s.Types[name]; def != nil && def.Kind == InterfaceKind {
+			sort.Strings(types)
+			def.PossibleTypes = types
+		}
+	}
+}
+
+// setDefaultOperationTypes sets the root operation types
+// for schemas that do not define them explicitly.
+func (s *Schema) setDefaultOperationTypes() {
+	if s.QueryType != "" || s.MutationType != "" || s.SubscriptionType != "" {
+		return
+	}
+	for kind, name := range map[OperationKind]string{Query: "Query", Mutation: "Mutation", Subscription: "Subscription"} {
+		if def := s.Types[name]; def == nil || def.Kind != ObjectKind {
+			continue
+		}
+		switch kind {
+		case Query:
+			s.QueryType = name
+		case Mutation:
+			s.MutationType = name
+		case Subscription:
+			s.SubscriptionType = name
+		}
+	}
+}
@@ -0,0 +1,595 @@
+package graphql
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// operationLocations maps kinds of operations to their directive locations.
+var operationLocations = map[OperationKind]string{
+	Query:        "QUERY",
+	Mutation:     "MUTATION",
+	Subscription: "SUBSCRIPTION",
+}
+
+type validator struct {
+	schema *Schema
+	doc    *Document
+	errs   Errors
+	seen   map[string]bool
+}
+
+// validationScope holds the state for validating the selections of an operation.
+// For fragments that are validated on their own, the operation is nil and
+// variables are not checked.
+type validationScope struct {
+	operation *Operation
+	variables map[string]*VariableDefinition
+	used      map[string]bool
+	visited   map[string]bool
+}
+
+// Validate checks the operations and fragments of a document against a schema.
+// It checks that fields, arguments, fragments, and directives exist, that
+// required arguments are provided with values of the correct type, and that
+// variables are defined, used, and of the correct type. The returned errors
+// are sorted by position.
+func Validate(schema *Schema, doc *Document) Errors {
+	v := &validator{schema: schema, doc: doc, seen: map[string]bool{}}
+
+	operationNames := map[string]bool{}
+	for _, op := range doc.Operations {
+		if op.Name == "" && len(doc.Operations) > 1 {
+			v.errorf(op.Pos, "anonymous operation must be the only defined operation")
+		}
+		if op.Name != "" {
+			if operationNames[op.Name] {
+				v.errorf(op.Pos, "there can be only one operation named %q", op.Name)
+			}
+			operationNames[op.Name] = true
+		}
+	}
+
+	fragmentNames := map[string]bool{}
+	for _, f := range doc.Fragments {
+		if fragmentNames[f.Name] {
+			v.errorf(f.Pos, "there can be only one fragment named %q", f.Name)
+		}
+		fragmentNames[f.Name] = true
+	}
+
+	usedFragments := map[string]bool{}
+	for _, op := range doc.Operations {
+		scope := v.validateOperation(op)
+		for name := range scope.visited {
+			usedFragments[name] = true
+		}
+	}
+
+	for _, f := range doc.Fragments {
+		if v.fragmentCycle(f, map[string]bool{}) {
+			v.errorf(f.Pos, "fragment %q cannot spread itself", f.Name)
+			continue
+		}
+		if !usedFragments[f.Name] {
+			v.errorf(f.Pos, "fragment %q is never used", f.Name)
+		}
+		def := v.typeCondition(f.TypeCondition, f.Pos)
+		scope := &validationScope{visited: map[string]bool{f.Name: true}}
+		v.validateDirectives(f.Directives, "FRAGMENT_DEFINITION", scope)
+		if def != nil {
+			v.validateSelectionSet(def, f.SelectionSet, scope)
+		}
+	}
+
+	sort.SliceStable(v.errs, func(i, j int) bool {
+		a, b := v.errs[i].Pos, v.errs[j].Pos
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return v.errs
+}
+
+func (v *validator) errorf(pos Pos, format string, args ...interface{}) {
+	err := &Error{Message: fmt.Sprintf(format, args...), Pos: pos}
+	if key := err.Error(); !v.seen[key] {
+		v.seen[key] = true
+		v.errs = append(v.errs, err)
+	}
+}
+
+func (v *validator) validateOperation(op *Operation) *validationScope {
+	scope := &validationScope{
+		operation: op,
+		variables: map[string]*VariableDefinition{},
+		used:      map[string]bool{},
+		visited:   map[string]bool{},
+	}
+
+	for _, def := range op.Variables {
+		if _, ok := scope.variables[def.Name]; ok {
+			v.errorf(def.Pos, "there can be only one variable named $%s", def.Name)
+			continue
+		}
+		scope.variables[def.Name] = def
+		typeDef := v.schema.Types[def.Type.NamedType()]
+		if typeDef == nil {
+			v.errorf(def.Type.Pos, "unknown type %q", def.Type.NamedType())
+			continue
+		}
+		if !typeDef.IsInput() {
+			v.errorf(def.Type.Pos, "variable $%s cannot be of non-input type %q", def.Name, def.Type)
+			continue
+		}
+		if def.DefaultValue != nil {
+			v.validateValue(def.DefaultValue, def.Type, scope)
+		}
+	}
+
+	v.validateDirectives(op.Directives, operationLocations[op.Kind], scope)
+
+	root := v.schema.OperationType(op.Kind)
+	if root == nil {
+		v.errorf(op.Pos, "schema does not support %s operations", op.Kind)
+		return scope
+	}
+	v.validateSelectionSet(root, op.SelectionSet, scope)
+
+	for _, def := range op.Variables {
+		if !scope.used[def.Name] {
+			v.errorf(def.Pos, "variable $%s is never used%s", def.Name, operationSuffix(op))
+		}
+	}
+	return scope
+}
+
+func (v *validator) validateSelectionSet(parent *Definition, selections []Selection, scope *validationScope) {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *Field:
+			v.validateField(parent, sel, scope)
+		case *InlineFragment:
+			v.validateDirectives(sel.Directives, "INLINE_FRAGMENT", scope)
+			def := parent
+			if sel.TypeCondition != "" {
+				def = v.typeCondition(sel.TypeCondition, sel.Pos)
+				if def == nil {
+					continue
+				}
+				v.checkSpreadPossible(parent, def, sel.Pos, "")
+			}
+			v.validateSelectionSet(def, sel.SelectionSet, scope)
+		case *FragmentSpread:
+			v.validateDirectives(sel.Directives, "FRAGMENT_SPREAD", scope)
+			f := v.doc.Fragment(sel.Name)
+			if f == nil {
+				v.errorf(sel.Pos, "unknown fragment %q", sel.Name)
+				continue
+			}
+			def := v.schema.Types[f.TypeCondition]
+			if def == nil || !def.IsComposite() {
+				continue
+			}
+			v.checkSpreadPossible(parent, def, sel.Pos, sel.Name)
+			if scope.visited[f.Name] {
+				continue
+			}
+			scope.visited[f.Name] = true
+			if scope.operation != nil {
+				v.validateSelectionSet(def, f.SelectionSet, scope)
+			}
+		}
+	}
+	v.checkFieldConflicts(parent, selections)
+}
+
+func (v *validator) validateField(parent *Definition, field *Field, scope *validationScope) {
+	def := v.schema.Field(parent, field.Name)
+	if def == nil {
+		v.errorf(field.Pos, "field %q does not exist on type %q%s", field.Name, parent.Name, suggestion(field.Name, fieldNames(parent)))
+		return
+	}
+
+	v.validateArguments(field.Arguments, def.Arguments, field.Pos, fmt.Sprintf("field %q", field.Name), scope)
+	v.validateDirectives(field.Directives, "FIELD", scope)
+
+	typeDef := v.schema.Types[def.Type.NamedType()]
+	if typeDef == nil {
+		return
+	}
+	if typeDef.IsLeaf() {
+		if len(field.SelectionSet) > 0 {
+			v.errorf(field.Pos, "field %q of type %q must not have a selection of subfields", field.Name, def.Type)
+		}
+		return
+	}
+	if len(field.SelectionSet) == 0 {
+		v.errorf(field.Pos, "field %q of type %q must have a selection of subfields", field.Name, def.Type)
+		return
+	}
+	v.validateSelectionSet(typeDef, field.SelectionSet, scope)
+}
+
+func (v *validator) validateArguments(args []*Argument, defs []*InputValueDefinition, pos Pos, target string, scope *validationScope) {
+	names := map[string]bool{}
+	for _, arg := range args {
+		if names[arg.Name] {
+			v.errorf(arg.Pos, "there can be only one argument named %q", arg.Name)
+			continue
+		}
+		names[arg.Name] = true
+
+		var def *InputValueDefinition
+		for _, d := range defs {
+			if d.Name == arg.Name {
+				def = d
+				break
+			}
+		}
+		if def == nil {
+			argNames := make([]string, len(defs))
+			for i, d := range defs {
+				argNames[i] = d.Name
+			}
+			v.errorf(arg.Pos, "unknown argument %q on %s%s", arg.Name, target, suggestion(arg.Name, argNames))
+			continue
+		}
+		v.validateValueWithDefault(arg.Value, def.Type, def.DefaultValue != nil, scope)
+	}
+
+	for _, def := range defs {
+		if def.Type.NonNull && def.DefaultValue == nil && !names[def.Name] {
+			v.errorf(pos, "argument %q of type %q is required on %s", def.Name, def.Type, target)
+		}
+	}
+}
+
+func (v *validator) validateDirectives(directives []*Directive, location string, scope *validationScope) {
+	names := map[string]bool{}
+	for _, d := range directives {
+		def := v.schema.Directives[d.Name]
+		if def == nil {
+			v.errorf(d.Pos, "unknown directive @%s", d.Name)
+			continue
+		}
+		if names[d.Name] && d.Name != "deprecated" {
+			v.errorf(d.Pos, "directive @%s can only be used once at this location", d.Name)
+		}
+		names[d.Name] = true
+		allowed := false
+		for _, l := range def.Locations {
+			if l == location {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			v.errorf(d.Pos, "directive @%s may not be used on %s", d.Name, location)
+		}
+		v.validateArguments(d.Arguments, def.Arguments, d.Pos, "directive @"+d.Name, scope)
+	}
+}
+
+func (v *validator) validateValue(value *Value, t *Type, scope *validationScope) {
+	v.validateValueWithDefault(value, t, false, scope)
+}
+
+// validateValueWithDefault checks that value can be used where a value of type
+// t is expected. hasDefault reports whether the location has a default value,
+// which allows nullable variables to be used for non-null locations.
+func (v *validator) validateValueWithDefault(value *Value, t *Type, hasDefault bool, scope *validationScope) {
+	if value.Kind == VariableValue {
+		if scope.operation == nil {
+			return
+		}
+		scope.used[value.Raw] = true
+		def, ok := scope.variables[value.Raw]
+		if !ok {
+			v.errorf(value.Pos, "variable $%s is not defined%s", value.Raw, operationSuffix(scope.operation))
+			return
+		}
+		varType := def.Type
+		if t.NonNull && !varType.NonNull && (hasDefault || (def.DefaultValue != nil && def.DefaultValue.Kind != NullValue)) {
+			nonNull := *varType
+			nonNull.NonNull = true
+			varType = &nonNull
+		}
+		if v.schema.Types[varType.NamedType()] != nil && !isSubType(varType, t) {
+			v.errorf(value.Pos, "variable $%s of type %q cannot be used where %q is expected", value.Raw, def.Type, t)
+		}
+		return
+	}
+
+	if value.Kind == NullValue {
+		if t.NonNull {
+			v.errorf(value.Pos, "expected value of type %q, found null", t)
+		}
+		return
+	}
+
+	if t.Elem != nil {
+		if value.Kind != ListValue {
+			v.validateValue(value, t.Elem, scope)
+			return
+		}
+		for _, item := range value.List {
+			v.validateValue(item, t.Elem, scope)
+		}
+		return
+	}
+
+	def := v.schema.Types[t.Name]
+	if def == nil {
+		return
+	}
+	invalid := func() {
+		v.errorf(value.Pos, "expected value of type %q, found %s", t, printValue(value))
+	}
+
+	switch def.Kind {
+	case InputObjectKind:
+		if value.Kind != ObjectValue {
+			invalid()
+			return
+		}
+		names := map[string]bool{}
+		for _, f := range value.Fields {
+			fieldDef := def.InputField(f.Name)
+			if fieldDef == nil {
+				inputNames := make([]string, len(def.InputFields))
+				for i, d := range def.InputFields {
+					inputNames[i] = d.Name
+				}
+				v.errorf(f.Pos, "field %q is not defined by type %q%s", f.Name, def.Name, suggestion(f.Name, inputNames))
+				continue
+			}
+			names[f.Name] = true
+			v.validateValueWithDefault(f.Value, fieldDef.Type, fieldDef.DefaultValue != nil, scope)
+		}
+		for _, fieldDef := range def.InputFields {
+			if fieldDef.Type.NonNull && fieldDef.DefaultValue == nil && !names[fieldDef.Name] {
+				v.errorf(value.Pos, "field %q of type %q is required by type %q", fieldDef.Name, fieldDef.Type, def.Name)
+			}
+		}
+	case EnumKind:
+		if value.Kind != EnumValue || def.EnumValue(value.Raw) == nil {
+			invalid()
+		}
+	case ScalarKind:
+		switch def.Name {
+		case "Int":
+			n, err := strconv.ParseInt(value.Raw, 10, 64)
+			if value.Kind != IntValue || err != nil || n > math.MaxInt32 || n < math.MinInt32 {
+				invalid()
+			}
+		case "Float":
+			if value.Kind != IntValue && value.Kind != FloatValue {
+				invalid()
+			}
+		case "String":
+			if value.Kind != StringValue {
+				invalid()
+			}
+		case "Boolean":
+			if value.Kind != BooleanValue {
+				invalid()
+			}
+		case "ID":
+			if value.Kind != StringValue && value.Kind != IntValue {
+				invalid()
+			}
+		}
+	default:
+		invalid()
+	}
+}
+
+// typeCondition returns the composite type named by a type condition,
+// or nil after reporting an error if there is no such type.
+func (v *validator) typeCondition(name string, pos Pos) *Definition {
+	def := v.schema.Types[name]
+	if def == nil {
+		v.errorf(pos, "unknown type %q", name)
+		return nil
+	}
+	if !def.IsComposite() {
+		v.errorf(pos, "fragment cannot condition on non-composite type %q", name)
+		return nil
+	}
+	return def
+}
+
+func (v *validator) checkSpreadPossible(parent, def *Definition, pos Pos, fragment string) {
+	possible := map[string]bool{}
+	for _, name := range v.schema.PossibleTypes(parent.Name) {
+		possible[name] = true
+	}
+	for _, name := range v.schema.PossibleTypes(def.Name) {
+		if possible[name] {
+			return
+		}
+	}
+	if fragment != "" {
+		v.errorf(pos, "fragment %q cannot be spread here as objects of type %q can never be of type %q", fragment, parent.Name, def.Name)
+		return
+	}
+	v.errorf(pos, "fragment cannot be spread here as objects of type %q can never be of type %q", parent.Name, def.Name)
+}
+
+// checkFieldConflicts reports fields in a selection set that have the same
+// response key but select different fields or pass different arguments.
+func (v *validator) checkFieldConflicts(parent *Definition, selections []Selection) {
+	fields := map[string]*Field{}
+	var collect func([]Selection, map[string]bool)
+	collect = func(selections []Selection, visited map[string]bool) {
+		for _, sel := range selections {
+			switch sel := sel.(type) {
+			case *Field:
+				key := sel.ResponseKey()
+				other, ok := fields[key]
+				if !ok {
+					fields[key] = sel
+					continue
+				}
+				if other.Name != sel.Name {
+					v.errorf(sel.Pos, "fields %q conflict because %q and %q are different fields", key, other.Name, sel.Name)
+				} else if printArguments(other.Arguments) != printArguments(sel.Arguments) {
+					v.errorf(sel.Pos, "fields %q conflict because they have differing arguments", key)
+				}
+			case *InlineFragment:
+				if sel.TypeCondition == "" || sel.TypeCondition == parent.Name {
+					collect(sel.SelectionSet, visited)
+				}
+			case *FragmentSpread:
+				f := v.doc.Fragment(sel.Name)
+				if f != nil && f.TypeCondition == parent.Name && !visited[f.Name] {
+					visited[f.Name] = true
+					collect(f.SelectionSet, visited)
+				}
+			}
+		}
+	}
+	collect(selections, map[string]bool{})
+}
+
+func (v *validator) fragmentCycle(f *Fragment, visiting map[string]bool) bool {
+	if visiting[f.Name] {
+		return true
+	}
+	visiting[f.Name] = true
+	defer delete(visiting, f.Name)
+	for _, name := range fragmentSpreads(f.SelectionSet) {
+		if next := v.doc.Fragment(name); next != nil && v.fragmentCycle(next, visiting) {
+			return true
+		}
+	}
+	return false
+}
+
+func fragmentSpreads(selections []Selection) []string {
+	var names []string
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *Field:
+			names = append(names, fragmentSpreads(sel.SelectionSet)...)
+		case *InlineFragment:
+			names = append(names, fragmentSpreads(sel.SelectionSet)...)
+		case *FragmentSpread:
+			names = append(names, sel.Name)
+		}
+	}
+	return names
+}
+
+// isSubType reports whether a value of type sub can be used where super is expected.
+func isSubType(sub, super *Type) bool {
+	if super.NonNull {
+		if !sub.NonNull {
+			return false
+		}
+		return isSubType(nullable(sub), nullable(super))
+	}
+	if sub.NonNull {
+		return isSubType(nullable(sub), super)
+	}
+	if super.Elem != nil {
+		return sub.Elem != nil && isSubType(sub.Elem, super.Elem)
+	}
+	return sub.Elem == nil && sub.Name == super.Name
+}
+
+func nullable(t *Type) *Type {
+	c := *t
+	c.NonNull = false
+	return &c
+}
+
+func operationSuffix(op *Operation) string {
+	if op.Name == "" {
+		return ""
+	}
+	return fmt.Sprintf(" by operation %q", op.Name)
+}
+
+func fieldNames(def *Definition) []string {
+	names := make([]string, 0, len(def.Fields)+1)
+	for _, f := range def.Fields {
+		names = append(names, f.Name)
+	}
+	return append(names, typenameField.Name)
+}
+
+// suggestion returns a hint naming the option that is closest to name,
+// or an empty string if no option is similar.
+func suggestion(name string, options []string) string {
+	best, bestDistance := "", len(name)/2+1
+	for _, option := range options {
+		if d := levenshtein(strings.ToLower(name), strings.ToLower(option)); d < bestDistance {
+			best, bestDistance = option, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean %q?", best)
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func printArguments(args []*Argument) string {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		parts[i] = a.Name + ": " + printValue(a.Value)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+func printValue(v *Value) string {
+	switch v.Kind {
+	case VariableValue:
+		return "$" + v.Raw
+	case StringValue:
+		return strconv.Quote(v.Raw)
+	case ListValue:
+		items := make([]string, len(v.List))
+		for i, item := range v.List {
+			items[i] = printValue(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case ObjectValue:
+		fields := make([]string, len(v.Fields))
+		for i, f := range v.Fields {
+			fields[i] = f.Name + ": " + printValue(f.Value)
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	default:
+		return v.Raw
+	}
+}
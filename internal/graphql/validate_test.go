@@ -0,0 +1,241 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	schema := loadTestSchema(t)
+
+	tests := []struct {
+		name     string
+		query    string
+		wantErrs []string
+	}{
+		{
+			name: "valid query",
+			query: `query RepositoryInfo($owner: String!, $name: String!, $states: [IssueState!] = [OPEN]) {
+  repository(owner: $owner, name: $name) {
+    __typename
+    id
+    name
+    issues(first: 10, states: $states) { nodes { ...IssueFields } }
+    ... on Starrable { stargazerCount }
+  }
+  search(query: "is:open", type: ISSUE) {
+    ... on Issue { number }
+    ... on Repository { url }
+  }
+}
+
+fragment IssueFields on Issue {
+  title
+  state
+}`,
+		},
+		{
+			name: "valid mutation",
+			query: `mutation AddStar($id: ID!) {
+  addStar(input: {starrableId: $id}) { starrable { stargazerCount } }
+}`,
+		},
+		{
+			name: "introspection meta fields",
+			query: `{
+  __schema { queryType { name } types { kind name ofType { name } } }
+  __type(name: "User") { name fields(includeDeprecated: true) { name type { kind } } }
+}`,
+		},
+		{
+			name:  "introspection meta fields outside the query root",
+			query: `{ __type { name } viewer { __schema { description } } }`,
+			wantErrs: []string{
+				`1:3: argument "name" of type "String!" is required on field "__type"`,
+				`1:28: field "__schema" does not exist on type "User"`,
+			},
+		},
+		{
+			name:  "unknown field with suggestion",
+			query: `{ viewer { logn } }`,
+			wantErrs: []string{
+				`1:12: field "logn" does not exist on type "User", did you mean "login"?`,
+			},
+		},
+		{
+			name:  "unknown argument and missing required argument",
+			query: `{ repository(owner: "cli", nme: "cli") { id } }`,
+			wantErrs: []string{
+				`1:3: argument "name" of type "String!" is required on field "repository"`,
+				`1:28: unknown argument "nme" on field "repository", did you mean "name"?`,
+			},
+		},
+		{
+			name:  "argument type mismatch",
+			query: `{ repository(owner: "cli", name: 1) { issues(first: "ten", states: [OPEN, MERGED]) { totalCount } } }`,
+			wantErrs: []string{
+				`1:34: expected value of type "String!", found 1`,
+				`1:53: expected value of type "Int", found "ten"`,
+				`1:75: expected value of type "IssueState!", found MERGED`,
+			},
+		},
+		{
+			name: "leaf and composite selections",
+			query: `{
+  viewer
+  repository(owner: "cli", name: "cli") { name { length } }
+}`,
+			wantErrs: []string{
+				`2:3: field "viewer" of type "User!" must have a selection of subfields`,
+				`3:43: field "name" of type "String!" must not have a selection of subfields`,
+			},
+		},
+		{
+			name:  "undefined and unused variables",
+			query: `query Q($owner: String!, $unused: Int) { repository(owner: $owner, name: $name) { id } }`,
+			wantErrs: []string{
+				`1:26: variable $unused is never used by operation "Q"`,
+				`1:74: variable $name is not defined by operation "Q"`,
+			},
+		},
+		{
+			name:  "variable type mismatch",
+			query: `query Q($owner: String, $first: String!) { repository(owner: $owner, name: "cli") { issues(first: $first) { totalCount } } }`,
+			wantErrs: []string{
+				`1:62: variable $owner of type "String" cannot be used where "String!" is expected`,
+				`1:99: variable $first of type "String!" cannot be used where "Int" is expected`,
+			},
+		},
+		{
+			name:  "nullable variable with default",
+			query: `query Q($owner: String = "cli") { repository(owner: $owner, name: "cli") { id } }`,
+		},
+		{
+			name:  "variable of non-input type",
+			query: `query Q($repo: Repository) { viewer { login } }`,
+			wantErrs: []string{
+				`1:9: variable $repo is never used by operation "Q"`,
+				`1:16: variable $repo cannot be of non-input type "Repository"`,
+			},
+		},
+		{
+			name: "fragment errors",
+			query: `{
+  viewer { ...RepoFields ...Missing }
+}
+
+fragment RepoFields on Repository { name }
+
+fragment Unused on User { login }`,
+			wantErrs: []string{
+				`2:12: fragment "RepoFields" cannot be spread here as objects of type "User" can never be of type "Repository"`,
+				`2:26: unknown fragment "Missing"`,
+				`7:1: fragment "Unused" is never used`,
+			},
+		},
+		{
+			name: "fragment cycle",
+			query: `{ viewer { ...A } }
+
+fragment A on User { ...B }
+
+fragment B on User { ...A }`,
+			wantErrs: []string{
+				`3:1: fragment "A" cannot spread itself`,
+				`5:1: fragment "B" cannot spread itself`,
+			},
+		},
+		{
+			name:  "inline fragment on unknown type",
+			query: `{ viewer { ... on Bot { login } } }`,
+			wantErrs: []string{
+				`1:12: unknown type "Bot"`,
+			},
+		},
+		{
+			name:  "directives",
+			query: `{ viewer @unknown { login @include(if: "yes") } }`,
+			wantErrs: []string{
+				`1:10: unknown directive @unknown`,
+				`1:40: expected value of type "Boolean!", found "yes"`,
+			},
+		},
+		{
+			name: "operation names",
+			query: `query A { viewer { login } }
+query A { viewer { id } }
+{ viewer { id } }`,
+			wantErrs: []string{
+				`2:1: there can be only one operation named "A"`,
+				`3:1: anonymous operation must be the only defined operation`,
+			},
+		},
+		{
+			name:  "conflicting fields",
+			query: `{ viewer { login: id login } }`,
+			wantErrs: []string{
+				`1:22: fields "login" conflict because "id" and "login" are different fields`,
+			},
+		},
+		{
+			name:  "unsupported operation",
+			query: `subscription S { viewer { login } }`,
+			wantErrs: []string{
+				`1:1: schema does not support subscription operations`,
+			},
+		},
+		{
+			name:  "missing required input field",
+			query: `mutation { addStar(input: {clientMutationId: "x", other: 1}) { clientMutationId } }`,
+			wantErrs: []string{
+				`1:27: field "starrableId" of type "ID!" is required by type "AddStarInput"`,
+				`1:51: field "other" is not defined by type "AddStarInput"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := ParseQuery("", tt.query)
+			assert.NoError(t, err)
+			var messages []string
+			for _, err := range Validate(schema, doc) {
+				messages = append(messages, err.Error())
+			}
+			assert.Equal(t, tt.wantErrs, messages)
+		})
+	}
+}
+
+func TestIsSubType(t *testing.T) {
+	tests := []struct {
+		sub, super string
+		want       bool
+	}{
+		{sub: "String", super: "String", want: true},
+		{sub: "String!", super: "String", want: true},
+		{sub: "String", super: "String!", want: false},
+		{sub: "[String!]!", super: "[String]", want: true},
+		{sub: "[String]", super: "[String!]", want: false},
+		{sub: "String", super: "[String]", want: false},
+		{sub: "Int", super: "String", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sub+" "+tt.super, func(t *testing.T) {
+			sub := parseTestType(t, tt.sub)
+			super := parseTestType(t, tt.super)
+			assert.Equal(t, tt.want, isSubType(sub, super))
+		})
+	}
+}
+
+func parseTestType(t *testing.T, s string) *Type {
+	t.Helper()
+	doc, err := ParseQuery("", "query Q($v: "+s+") { f }")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc.Operations[0].Variables[0].Type
+}
@@ -0,0 +1,79 @@
+// Package restapi implements helpers shared by the packages that model
+// GitHub REST API resources.
+package restapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/repository"
+)
+
+const (
+	defaultLimit = 30
+	maxPerPage   = 100
+)
+
+var linkRE = regexp.MustCompile(`<([^>]+)>;\s*rel="([^"]+)"`)
+
+// List fetches pages of path, following the next links of the responses,
+// until limit items have been returned or there are no more pages. The
+// items function extracts the items from each decoded page.
+func List[P any, T any](ctx context.Context, client *api.RESTClient, path string, query url.Values, limit int, items func(*P) []T) ([]T, error) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("per_page", strconv.Itoa(min(limit, maxPerPage)))
+
+	var result []T
+	next := path + "?" + q.Encode()
+	for next != "" && len(result) < limit {
+		resp, err := client.RequestWithContext(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page P
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, items(&page)...)
+		next = nextPage(resp.Header.Get("Link"))
+	}
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// nextPage returns the URL of the next page from a Link header,
+// or an empty string if there is no next page.
+func nextPage(link string) string {
+	for _, m := range linkRE.FindAllStringSubmatch(link, -1) {
+		if m[2] == "next" {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// RepoPath returns the path of a repository, followed by the
+// formatted subpath if format is not empty.
+func RepoPath(repo repository.Repository, format string, args ...interface{}) string {
+	path := fmt.Sprintf("repos/%s/%s", url.PathEscape(repo.Owner), url.PathEscape(repo.Name))
+	if format == "" {
+		return path
+	}
+	return path + "/" + fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,41 @@
+package restapi
+
+import (
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextPage(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{
+			name: "no header",
+		},
+		{
+			name: "next and last",
+			link: `<https://api.github.com/repos/cli/cli/issues?page=2>; rel="next", <https://api.github.com/repos/cli/cli/issues?page=5>; rel="last"`,
+			want: "https://api.github.com/repos/cli/cli/issues?page=2",
+		},
+		{
+			name: "last page",
+			link: `<https://api.github.com/repos/cli/cli/issues?page=1>; rel="first", <https://api.github.com/repos/cli/cli/issues?page=4>; rel="prev"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextPage(tt.link))
+		})
+	}
+}
+
+func TestRepoPath(t *testing.T) {
+	repo := repository.Repository{Host: "github.com", Owner: "cli", Name: "go gh"}
+	assert.Equal(t, "repos/cli/go%20gh", RepoPath(repo, ""))
+	assert.Equal(t, "repos/cli/go%20gh/issues/12/comments", RepoPath(repo, "issues/%d/comments", 12))
+}
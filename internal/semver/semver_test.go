@@ -0,0 +1,76 @@
+package semver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Version
+		wantErr bool
+	}{
+		{in: "2.40.1", want: Version{Major: 2, Minor: 40, Patch: 1}},
+		{in: "v2.40.1", want: Version{Major: 2, Minor: 40, Patch: 1}},
+		{in: "2.41", want: Version{Major: 2, Minor: 41}},
+		{in: "3", want: Version{Major: 3}},
+		{in: "2.41.0-pre.1", want: Version{Major: 2, Minor: 41, Prerelease: "pre.1"}},
+		{in: "2.40.1-12-gabc123", want: Version{Major: 2, Minor: 40, Patch: 1, Prerelease: "12-gabc123"}},
+		{in: "1.0.0+build.5", want: Version{Major: 1, Build: "build.5"}},
+		{in: "", wantErr: true},
+		{in: "DEV", wantErr: true},
+		{in: "1.2.3.4", wantErr: true},
+		{in: "1.02.3", wantErr: true},
+		{in: "1.-2.3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			v, err := Parse(tt.in)
+			if tt.wantErr {
+				assert.EqualError(t, err, fmt.Sprintf("invalid version %q", tt.in))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, v)
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "2.40.1", b: "2.40.1", want: 0},
+		{a: "2.40.1", b: "2.40.0", want: 1},
+		{a: "2.9.0", b: "2.10.0", want: -1},
+		{a: "3.0.0", b: "2.99.99", want: 1},
+		{a: "2.41.0-pre.1", b: "2.41.0", want: -1},
+		{a: "2.41.0-pre.2", b: "2.41.0-pre.10", want: -1},
+		{a: "1.0.0-alpha", b: "1.0.0-alpha.1", want: -1},
+		{a: "1.0.0-alpha.beta", b: "1.0.0-alpha.1", want: 1},
+		{a: "1.0.0-rc.1", b: "1.0.0-beta.11", want: 1},
+		{a: "1.0.0+a", b: "1.0.0+b", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			a, err := Parse(tt.a)
+			assert.NoError(t, err)
+			b, err := Parse(tt.b)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, a.Compare(b))
+			assert.Equal(t, -tt.want, b.Compare(a))
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	v, err := Parse("v2.41-pre.1+abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.41.0-pre.1+abc", v.String())
+}
@@ -0,0 +1,133 @@
+// Package actions provides helpers for working with GitHub Actions workflow
+// runs, jobs, and artifacts through the REST API.
+package actions
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cli/go-gh/v2/internal/restapi"
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/asciisanitizer"
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"golang.org/x/text/transform"
+)
+
+const defaultTailInterval = 3 * time.Second
+
+// TailOptions holds options for tailing a job log.
+type TailOptions struct {
+	// Interval is how often the job is polled for new log output.
+	// Default is 3 seconds.
+	Interval time.Duration
+}
+
+// TailJobLog writes the log of a workflow job to w as it is produced, polling
+// for new output until the job completes or ctx is done. Only the part of the
+// log that has not been written yet is requested on each poll. The log is
+// sanitized so that it is safe for display in a terminal.
+func TailJobLog(ctx context.Context, client *api.RESTClient, repo repository.Repository, jobID int64, w io.Writer, opts TailOptions) error {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultTailInterval
+	}
+	logPath := restapi.RepoPath(repo, "actions/jobs/%d/logs", jobID)
+
+	var offset int64
+	for {
+		// Check the status before reading the log so that the final read
+		// of a completed job includes all of its output.
+		job, err := GetJob(ctx, client, repo, jobID)
+		if err != nil {
+			return err
+		}
+
+		n, err := copyLog(ctx, client, logPath, offset, w, job.IsCompleted())
+		offset += n
+		if err != nil {
+			return err
+		}
+
+		if job.IsCompleted() {
+			return nil
+		}
+
+		if err := sleep(ctx, opts.Interval); err != nil {
+			return err
+		}
+	}
+}
+
+// sleep waits for d to elapse or ctx to be done, whichever comes first.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// copyLog copies the log from offset onwards to w, returning the number of
+// bytes of the log that were written. A log that is not available yet is
+// treated as having no new output. Unless final is set, a character that is
+// cut off at the end of the log is left for the next read.
+func copyLog(ctx context.Context, client *api.RESTClient, path string, offset int64, w io.Writer, final bool) (int64, error) {
+	body, err := client.StreamWithContext(ctx, http.MethodGet, path, nil, api.StreamOptions{Offset: offset})
+	if err != nil {
+		var httpErr *api.HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer body.Close()
+
+	// Sanitizing changes the length of the output, so the log is written a
+	// line at a time and only the lines that were written count towards
+	// where the next read should start.
+	var n int64
+	r := bufio.NewReader(body)
+	for {
+		line, readErr := r.ReadBytes('\n')
+		if readErr == io.EOF && !final {
+			line = line[:len(line)-partialRuneLen(line)]
+		}
+		if len(line) > 0 {
+			out, _, err := transform.Bytes(&asciisanitizer.Sanitizer{}, line)
+			if err != nil {
+				return n, err
+			}
+			if _, err := w.Write(out); err != nil {
+				return n, err
+			}
+			n += int64(len(line))
+		}
+		if readErr == io.EOF {
+			return n, nil
+		}
+		if readErr != nil {
+			return n, readErr
+		}
+	}
+}
+
+// partialRuneLen returns the length of the incomplete UTF-8 encoded rune at
+// the end of b, if any.
+func partialRuneLen(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if utf8.FullRune(b[i:]) {
+				return 0
+			}
+			return len(b) - i
+		}
+	}
+	return 0
+}
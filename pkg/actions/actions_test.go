@@ -0,0 +1,156 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/api/apitest"
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTailJobLog(t *testing.T) {
+	tests := []struct {
+		name       string
+		chunks     []string
+		wantOut    string
+		wantRanges []string
+	}{
+		{
+			name:       "sanitizes control characters",
+			chunks:     []string{"", "Run tests\n", "\x1b[31mFAIL\x1b[0m\n", "Done\n"},
+			wantOut:    "Run tests\n^[[31mFAIL^[[0m\nDone\n",
+			wantRanges: []string{"", "bytes=10-", "bytes=24-"},
+		},
+		{
+			name:       "replaces invalid UTF-8",
+			chunks:     []string{"", "Run \xfftests\n", "caf\xc3", "\xa9 done\n"},
+			wantOut:    "Run \uFFFDtests\ncaf\u00e9 done\n",
+			wantRanges: []string{"", "bytes=11-", "bytes=14-"},
+		},
+		{
+			name:       "replaces incomplete character at end of completed log",
+			chunks:     []string{"", "Run tests\n", "caf\xc3"},
+			wantOut:    "Run tests\ncaf\uFFFD",
+			wantRanges: []string{"", "bytes=10-"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := apitest.NewServer("token")
+
+			var mu sync.Mutex
+			polls := 0
+			var ranges []string
+			s.HandleFunc("GET", "/repos/{owner}/{repo}/actions/jobs/{job}", func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				polls++
+				status := "in_progress"
+				if polls >= len(tt.chunks) {
+					status = "completed"
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"id": %s, "status": %q}`, apitest.PathParam(r, "job"), status)
+			})
+			s.HandleFunc("GET", "/repos/{owner}/{repo}/actions/jobs/{job}/logs", func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				if polls == 1 {
+					// The log is not available until the job has started.
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				ranges = append(ranges, r.Header.Get("Range"))
+				serveLog(w, r, strings.Join(tt.chunks[:polls], ""))
+			})
+
+			client, err := api.NewRESTClient(s.ClientOptions())
+			assert.NoError(t, err)
+
+			out := &bytes.Buffer{}
+			repo := repository.Repository{Host: "github.com", Owner: "cli", Name: "cli"}
+			err = TailJobLog(context.Background(), client, repo, 42, out, TailOptions{Interval: time.Millisecond})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantOut, out.String())
+			assert.Equal(t, tt.wantRanges, ranges)
+			assert.Equal(t, len(tt.chunks), polls)
+		})
+	}
+}
+
+func TestCopyLogWriteError(t *testing.T) {
+	s := apitest.NewServer("token")
+	s.HandleFunc("GET", "/logs", func(w http.ResponseWriter, r *http.Request) {
+		serveLog(w, r, "line 1\nline 2\n")
+	})
+	client, err := api.NewRESTClient(s.ClientOptions())
+	assert.NoError(t, err)
+
+	// Only the lines that were written are counted.
+	w := &failingWriter{limit: 1}
+	n, err := copyLog(context.Background(), client, "logs", 0, w, false)
+	assert.EqualError(t, err, "write failed")
+	assert.Equal(t, int64(7), n)
+	assert.Equal(t, "line 1\n", w.buf.String())
+}
+
+func TestTailJobLogCanceled(t *testing.T) {
+	s := apitest.NewServer("token")
+	s.HandleFunc("GET", "/repos/{owner}/{repo}/actions/jobs/{job}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status": "queued"}`)
+	})
+	s.HandleFunc("GET", "/repos/{owner}/{repo}/actions/jobs/{job}/logs", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	client, err := api.NewRESTClient(s.ClientOptions())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	repo := repository.Repository{Host: "github.com", Owner: "cli", Name: "cli"}
+	err = TailJobLog(ctx, client, repo, 42, &bytes.Buffer{}, TailOptions{Interval: time.Millisecond})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// serveLog serves the part of log requested by the Range header of r.
+func serveLog(w http.ResponseWriter, r *http.Request, log string) {
+	var offset int
+	if rng := r.Header.Get("Range"); rng != "" {
+		offset, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	if offset >= len(log) {
+		w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+		return
+	}
+	if offset > 0 {
+		w.WriteHeader(http.StatusPartialContent)
+	}
+	fmt.Fprint(w, log[offset:])
+}
+
+// failingWriter fails every write after the first limit writes.
+type failingWriter struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.limit == 0 {
+		return 0, errors.New("write failed")
+	}
+	w.limit--
+	return w.buf.Write(p)
+}
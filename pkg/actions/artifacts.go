@@ -0,0 +1,59 @@
+package actions
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cli/go-gh/v2/internal/restapi"
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/repository"
+)
+
+// Artifact is a file archive uploaded by a workflow run.
+type Artifact struct {
+	ID                 int64     `json:"id"`
+	Name               string    `json:"name"`
+	SizeInBytes        int64     `json:"size_in_bytes"`
+	Expired            bool      `json:"expired"`
+	ArchiveDownloadURL string    `json:"archive_download_url"`
+	CreatedAt          time.Time `json:"created_at"`
+	ExpiresAt          time.Time `json:"expires_at"`
+}
+
+// ListArtifactsOptions holds options for listing artifacts.
+type ListArtifactsOptions struct {
+	// Name filters the artifacts by name.
+	Name string
+
+	// Limit is the maximum number of artifacts to return.
+	// Default is 30.
+	Limit int
+}
+
+// ListArtifacts returns the artifacts of a workflow run.
+func ListArtifacts(ctx context.Context, client *api.RESTClient, repo repository.Repository, runID int64, opts ListArtifactsOptions) ([]Artifact, error) {
+	query := url.Values{}
+	if opts.Name != "" {
+		query.Set("name", opts.Name)
+	}
+	return restapi.List(ctx, client, restapi.RepoPath(repo, "actions/runs/%d/artifacts", runID), query, opts.Limit, func(page *struct {
+		Items []Artifact `json:"artifacts"`
+	}) []Artifact {
+		return page.Items
+	})
+}
+
+// DownloadArtifact writes the zip archive of an artifact to w. The API
+// redirects to the archive, which is streamed rather than buffered.
+// It returns the number of bytes written.
+func DownloadArtifact(ctx context.Context, client *api.RESTClient, repo repository.Repository, artifactID int64, w io.Writer) (int64, error) {
+	body, err := client.StreamWithContext(ctx, http.MethodGet, restapi.RepoPath(repo, "actions/artifacts/%d/zip", artifactID), nil, api.StreamOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+	return io.Copy(w, body)
+}
@@ -0,0 +1,49 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/api/apitest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListArtifacts(t *testing.T) {
+	s := apitest.NewServer("token")
+	s.HandleFunc("GET", "/repos/{owner}/{repo}/actions/runs/{run}/artifacts", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "7", apitest.PathParam(r, "run"))
+		assert.Equal(t, "logs", r.URL.Query().Get("name"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"total_count": 1, "artifacts": [{"id": 3, "name": "logs", "size_in_bytes": 1024, "expired": false}]}`)
+	})
+	client, err := api.NewRESTClient(s.ClientOptions())
+	assert.NoError(t, err)
+
+	artifacts, err := ListArtifacts(context.Background(), client, testRepo, 7, ListArtifactsOptions{Name: "logs"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Artifact{{ID: 3, Name: "logs", SizeInBytes: 1024}}, artifacts)
+}
+
+func TestDownloadArtifact(t *testing.T) {
+	archive := "PK\x03\x04\x00\x1b[31m"
+	s := apitest.NewServer("token")
+	s.HandleFunc("GET", "/repos/{owner}/{repo}/actions/artifacts/{artifact}/zip", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/storage/artifact.zip", http.StatusFound)
+	})
+	s.HandleFunc("GET", "/storage/artifact.zip", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/zip")
+		fmt.Fprint(w, archive)
+	})
+	client, err := api.NewRESTClient(s.ClientOptions())
+	assert.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	n, err := DownloadArtifact(context.Background(), client, testRepo, 3, out)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(archive)), n)
+	assert.Equal(t, archive, out.String())
+}
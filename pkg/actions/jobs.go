@@ -0,0 +1,80 @@
+package actions
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cli/go-gh/v2/internal/restapi"
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/repository"
+)
+
+// Job is a job of a workflow run.
+type Job struct {
+	ID          int64     `json:"id"`
+	RunID       int64     `json:"run_id"`
+	RunAttempt  int       `json:"run_attempt"`
+	Name        string    `json:"name"`
+	Status      string    `json:"status"`
+	Conclusion  string    `json:"conclusion"`
+	HeadSHA     string    `json:"head_sha"`
+	URL         string    `json:"html_url"`
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+	Steps       []Step    `json:"steps"`
+}
+
+// IsCompleted reports whether the job has finished.
+func (j *Job) IsCompleted() bool {
+	return j.Status == StatusCompleted
+}
+
+// Step is a step of a job.
+type Step struct {
+	Number      int       `json:"number"`
+	Name        string    `json:"name"`
+	Status      string    `json:"status"`
+	Conclusion  string    `json:"conclusion"`
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// ListJobsOptions holds options for listing the jobs of a workflow run.
+type ListJobsOptions struct {
+	// AllAttempts includes the jobs of every attempt of the run
+	// instead of only the jobs of the latest attempt.
+	AllAttempts bool
+
+	// Limit is the maximum number of jobs to return.
+	// Default is 30.
+	Limit int
+}
+
+// ListJobs returns the jobs of a workflow run.
+func ListJobs(ctx context.Context, client *api.RESTClient, repo repository.Repository, runID int64, opts ListJobsOptions) ([]Job, error) {
+	query := url.Values{}
+	if opts.AllAttempts {
+		query.Set("filter", "all")
+	}
+	return restapi.List(ctx, client, restapi.RepoPath(repo, "actions/runs/%d/jobs", runID), query, opts.Limit, func(page *struct {
+		Items []Job `json:"jobs"`
+	}) []Job {
+		return page.Items
+	})
+}
+
+// GetJob returns a job of a workflow run.
+func GetJob(ctx context.Context, client *api.RESTClient, repo repository.Repository, jobID int64) (*Job, error) {
+	var job Job
+	if err := client.DoWithContext(ctx, http.MethodGet, restapi.RepoPath(repo, "actions/jobs/%d", jobID), nil, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// RerunJob re-runs a job and the jobs that depend on it.
+func RerunJob(ctx context.Context, client *api.RESTClient, repo repository.Repository, jobID int64) error {
+	return post(ctx, client, restapi.RepoPath(repo, "actions/jobs/%d/rerun", jobID))
+}
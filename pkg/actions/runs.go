@@ -0,0 +1,136 @@
+package actions
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cli/go-gh/v2/internal/restapi"
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/repository"
+)
+
+// Statuses of workflow runs and jobs.
+const (
+	StatusCompleted  = "completed"
+	StatusInProgress = "in_progress"
+	StatusPending    = "pending"
+	StatusQueued     = "queued"
+	StatusRequested  = "requested"
+	StatusWaiting    = "waiting"
+)
+
+// Conclusions of completed workflow runs and jobs.
+const (
+	ConclusionActionRequired = "action_required"
+	ConclusionCancelled      = "cancelled"
+	ConclusionFailure        = "failure"
+	ConclusionNeutral        = "neutral"
+	ConclusionSkipped        = "skipped"
+	ConclusionStale          = "stale"
+	ConclusionSuccess        = "success"
+	ConclusionTimedOut       = "timed_out"
+)
+
+// WorkflowRun is a single run of a workflow.
+type WorkflowRun struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	DisplayTitle string    `json:"display_title"`
+	WorkflowID   int64     `json:"workflow_id"`
+	RunNumber    int       `json:"run_number"`
+	RunAttempt   int       `json:"run_attempt"`
+	Event        string    `json:"event"`
+	Status       string    `json:"status"`
+	Conclusion   string    `json:"conclusion"`
+	HeadBranch   string    `json:"head_branch"`
+	HeadSHA      string    `json:"head_sha"`
+	URL          string    `json:"html_url"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	RunStartedAt time.Time `json:"run_started_at"`
+}
+
+// IsCompleted reports whether the run has finished.
+func (r *WorkflowRun) IsCompleted() bool {
+	return r.Status == StatusCompleted
+}
+
+// ListRunsOptions holds options for listing workflow runs.
+type ListRunsOptions struct {
+	// Workflow limits the runs to a workflow, specified by its ID or file name.
+	Workflow string
+
+	// Actor, Branch, Event, Status, and HeadSHA filter the runs.
+	Actor   string
+	Branch  string
+	Event   string
+	Status  string
+	HeadSHA string
+
+	// Limit is the maximum number of runs to return.
+	// Default is 30.
+	Limit int
+}
+
+// ListWorkflowRuns returns the most recent workflow runs of a repository,
+// fetching as many pages as necessary to reach the limit.
+func ListWorkflowRuns(ctx context.Context, client *api.RESTClient, repo repository.Repository, opts ListRunsOptions) ([]WorkflowRun, error) {
+	path := restapi.RepoPath(repo, "actions/runs")
+	if opts.Workflow != "" {
+		path = restapi.RepoPath(repo, "actions/workflows/%s/runs", url.PathEscape(opts.Workflow))
+	}
+	query := url.Values{}
+	for key, value := range map[string]string{
+		"actor":    opts.Actor,
+		"branch":   opts.Branch,
+		"event":    opts.Event,
+		"status":   opts.Status,
+		"head_sha": opts.HeadSHA,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+
+	return restapi.List(ctx, client, path, query, opts.Limit, func(page *struct {
+		Items []WorkflowRun `json:"workflow_runs"`
+	}) []WorkflowRun {
+		return page.Items
+	})
+}
+
+// GetWorkflowRun returns a workflow run.
+func GetWorkflowRun(ctx context.Context, client *api.RESTClient, repo repository.Repository, runID int64) (*WorkflowRun, error) {
+	var run WorkflowRun
+	if err := client.DoWithContext(ctx, http.MethodGet, restapi.RepoPath(repo, "actions/runs/%d", runID), nil, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// RerunWorkflowRun re-runs every job of a workflow run.
+func RerunWorkflowRun(ctx context.Context, client *api.RESTClient, repo repository.Repository, runID int64) error {
+	return post(ctx, client, restapi.RepoPath(repo, "actions/runs/%d/rerun", runID))
+}
+
+// RerunFailedJobs re-runs the failed jobs of a workflow run and the jobs that depend on them.
+func RerunFailedJobs(ctx context.Context, client *api.RESTClient, repo repository.Repository, runID int64) error {
+	return post(ctx, client, restapi.RepoPath(repo, "actions/runs/%d/rerun-failed-jobs", runID))
+}
+
+// CancelWorkflowRun cancels a workflow run.
+func CancelWorkflowRun(ctx context.Context, client *api.RESTClient, repo repository.Repository, runID int64) error {
+	return post(ctx, client, restapi.RepoPath(repo, "actions/runs/%d/cancel", runID))
+}
+
+// post issues a POST request without a body, discarding the response
+// since these endpoints may respond without a JSON body.
+func post(ctx context.Context, client *api.RESTClient, path string) error {
+	resp, err := client.RequestWithContext(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
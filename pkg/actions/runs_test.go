@@ -0,0 +1,183 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/api/apitest"
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRepo = repository.Repository{Host: "github.com", Owner: "cli", Name: "cli"}
+
+func TestListWorkflowRuns(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      ListRunsOptions
+		total     int
+		wantPath  string
+		wantCount int
+		wantPages []string
+	}{
+		{
+			name:      "default limit",
+			total:     50,
+			wantPath:  "/repos/cli/cli/actions/runs",
+			wantCount: 30,
+			wantPages: []string{"1"},
+		},
+		{
+			name:      "limit across pages",
+			opts:      ListRunsOptions{Limit: 150},
+			total:     250,
+			wantPath:  "/repos/cli/cli/actions/runs",
+			wantCount: 150,
+			wantPages: []string{"1", "2"},
+		},
+		{
+			name:      "fewer runs than limit",
+			opts:      ListRunsOptions{Limit: 150},
+			total:     120,
+			wantPath:  "/repos/cli/cli/actions/runs",
+			wantCount: 120,
+			wantPages: []string{"1", "2"},
+		},
+		{
+			name:      "workflow and filters",
+			opts:      ListRunsOptions{Workflow: "ci.yml", Branch: "trunk", Limit: 2},
+			total:     5,
+			wantPath:  "/repos/cli/cli/actions/workflows/ci.yml/runs",
+			wantCount: 2,
+			wantPages: []string{"1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := apitest.NewServer("token")
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				query := r.URL.Query()
+				perPage, _ := strconv.Atoi(query.Get("per_page"))
+				page, err := strconv.Atoi(query.Get("page"))
+				if err != nil {
+					page = 1
+				}
+				runs := []WorkflowRun{}
+				for id := (page-1)*perPage + 1; id <= min(page*perPage, tt.total); id++ {
+					runs = append(runs, WorkflowRun{ID: int64(id)})
+				}
+				if page*perPage < tt.total {
+					query.Set("page", strconv.Itoa(page+1))
+					w.Header().Set("Link", fmt.Sprintf(`<https://%s%s?%s>; rel="next"`, r.Host, r.URL.Path, query.Encode()))
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"total_count": %d, "workflow_runs": %s}`, tt.total, mustJSON(t, runs))
+			}
+			s.HandleFunc("GET", "/repos/{owner}/{repo}/actions/runs", handler)
+			s.HandleFunc("GET", "/repos/{owner}/{repo}/actions/workflows/{workflow}/runs", handler)
+			client, err := api.NewRESTClient(s.ClientOptions())
+			assert.NoError(t, err)
+
+			runs, err := ListWorkflowRuns(context.Background(), client, testRepo, tt.opts)
+			assert.NoError(t, err)
+			assert.Len(t, runs, tt.wantCount)
+			for i, run := range runs {
+				assert.Equal(t, int64(i+1), run.ID)
+			}
+
+			var pages []string
+			for _, c := range s.Calls() {
+				assert.Equal(t, tt.wantPath, c.Path)
+				page := c.Query.Get("page")
+				if page == "" {
+					page = "1"
+				}
+				pages = append(pages, page)
+				if tt.opts.Branch != "" {
+					assert.Equal(t, tt.opts.Branch, c.Query.Get("branch"))
+				}
+			}
+			assert.Equal(t, tt.wantPages, pages)
+		})
+	}
+}
+
+func TestRerunAndCancel(t *testing.T) {
+	tests := []struct {
+		name     string
+		do       func(*api.RESTClient) error
+		wantPath string
+	}{
+		{
+			name: "rerun run",
+			do: func(c *api.RESTClient) error {
+				return RerunWorkflowRun(context.Background(), c, testRepo, 7)
+			},
+			wantPath: "/repos/cli/cli/actions/runs/7/rerun",
+		},
+		{
+			name: "rerun failed jobs",
+			do: func(c *api.RESTClient) error {
+				return RerunFailedJobs(context.Background(), c, testRepo, 7)
+			},
+			wantPath: "/repos/cli/cli/actions/runs/7/rerun-failed-jobs",
+		},
+		{
+			name: "rerun job",
+			do: func(c *api.RESTClient) error {
+				return RerunJob(context.Background(), c, testRepo, 9)
+			},
+			wantPath: "/repos/cli/cli/actions/jobs/9/rerun",
+		},
+		{
+			name: "cancel run",
+			do: func(c *api.RESTClient) error {
+				return CancelWorkflowRun(context.Background(), c, testRepo, 7)
+			},
+			wantPath: "/repos/cli/cli/actions/runs/7/cancel",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := apitest.NewServer("token")
+			s.HandleFunc("POST", tt.wantPath, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+			})
+			client, err := api.NewRESTClient(s.ClientOptions())
+			assert.NoError(t, err)
+
+			assert.NoError(t, tt.do(client))
+			assert.True(t, s.Called("POST", tt.wantPath))
+		})
+	}
+}
+
+func TestRerunWorkflowRunError(t *testing.T) {
+	s := apitest.NewServer("token")
+	s.HandleFunc("POST", "/repos/{owner}/{repo}/actions/runs/{run}/rerun", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message": "This workflow run cannot be rerun"}`)
+	})
+	client, err := api.NewRESTClient(s.ClientOptions())
+	assert.NoError(t, err)
+
+	err = RerunWorkflowRun(context.Background(), client, testRepo, 7)
+	var httpErr *api.HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusForbidden, httpErr.StatusCode)
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	assert.NoError(t, err)
+	return string(b)
+}
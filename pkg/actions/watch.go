@@ -0,0 +1,72 @@
+package actions
+
+import (
+	"context"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/repository"
+)
+
+const (
+	defaultWatchInterval    = 3 * time.Second
+	defaultWatchMaxInterval = 30 * time.Second
+	watchBackoffFactor      = 1.5
+)
+
+// WatchOptions holds options for watching a workflow run.
+type WatchOptions struct {
+	// Interval is the initial delay between polls of the run.
+	// Default is 3 seconds.
+	Interval time.Duration
+
+	// MaxInterval is the longest delay between polls. The delay grows
+	// while the run is unchanged and is reset whenever its status or
+	// conclusion changes.
+	// Default is 30 seconds.
+	MaxInterval time.Duration
+
+	// OnChange is called with the previous and current state of the run
+	// each time its status or conclusion changes. On the first poll prev
+	// is nil.
+	OnChange func(prev, curr *WorkflowRun)
+}
+
+// WatchRun polls a workflow run until it completes or ctx is done,
+// returning the completed run.
+func WatchRun(ctx context.Context, client *api.RESTClient, repo repository.Repository, runID int64, opts WatchOptions) (*WorkflowRun, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultWatchInterval
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = defaultWatchMaxInterval
+	}
+	opts.MaxInterval = max(opts.MaxInterval, opts.Interval)
+
+	var prev *WorkflowRun
+	interval := opts.Interval
+	for {
+		run, err := GetWorkflowRun(ctx, client, repo, runID)
+		if err != nil {
+			return nil, err
+		}
+
+		if prev == nil || prev.Status != run.Status || prev.Conclusion != run.Conclusion {
+			if opts.OnChange != nil {
+				opts.OnChange(prev, run)
+			}
+			interval = opts.Interval
+		} else {
+			interval = min(time.Duration(float64(interval)*watchBackoffFactor), opts.MaxInterval)
+		}
+		prev = run
+
+		if run.IsCompleted() {
+			return run, nil
+		}
+
+		if err := sleep(ctx, interval); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/api/apitest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchRun(t *testing.T) {
+	states := []WorkflowRun{
+		{ID: 7, Status: StatusQueued},
+		{ID: 7, Status: StatusQueued},
+		{ID: 7, Status: StatusInProgress},
+		{ID: 7, Status: StatusInProgress},
+		{ID: 7, Status: StatusInProgress},
+		{ID: 7, Status: StatusCompleted, Conclusion: ConclusionFailure},
+	}
+	var mu sync.Mutex
+	polls := 0
+	s := apitest.NewServer("token")
+	s.HandleFunc("GET", "/repos/{owner}/{repo}/actions/runs/{run}", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(states[polls])
+		polls++
+	})
+	client, err := api.NewRESTClient(s.ClientOptions())
+	assert.NoError(t, err)
+
+	var transitions []string
+	run, err := WatchRun(context.Background(), client, testRepo, 7, WatchOptions{
+		Interval:    time.Millisecond,
+		MaxInterval: 2 * time.Millisecond,
+		OnChange: func(prev, curr *WorkflowRun) {
+			from := "<nil>"
+			if prev != nil {
+				from = prev.Status
+			}
+			transitions = append(transitions, from+" -> "+curr.Status+" "+curr.Conclusion)
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, ConclusionFailure, run.Conclusion)
+	assert.Equal(t, len(states), polls)
+	assert.Equal(t, []string{
+		"<nil> -> queued ",
+		"queued -> in_progress ",
+		"in_progress -> completed failure",
+	}, transitions)
+}
+
+func TestWatchRunCanceled(t *testing.T) {
+	s := apitest.NewServer("token")
+	s.HandleFunc("GET", "/repos/{owner}/{repo}/actions/runs/{run}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(WorkflowRun{ID: 7, Status: StatusQueued})
+	})
+	client, err := api.NewRESTClient(s.ClientOptions())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = WatchRun(ctx, client, testRepo, 7, WatchOptions{Interval: time.Millisecond})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
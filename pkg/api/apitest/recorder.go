@@ -0,0 +1,323 @@
+// Package apitest is a set of types for testing code that uses the api
+// package without making requests to the GitHub API.
+package apitest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/cli/go-gh/v2/internal/graphql"
+)
+
+const base64Encoding = "base64"
+
+var scrubbedHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Proxy-Authorization",
+	"Set-Cookie",
+}
+
+// Mode specifies whether a Recorder records or replays interactions.
+type Mode int
+
+const (
+	// ModeAuto replays interactions if the fixture file exists,
+	// and records them otherwise.
+	ModeAuto Mode = iota
+
+	// ModeReplay replays interactions from the fixture file. Requests that
+	// do not match a recorded interaction fail without being sent.
+	ModeReplay
+
+	// ModeRecord sends requests using the Transport and records the interactions
+	// so that they can be written to the fixture file with Save.
+	ModeRecord
+)
+
+// RecorderOptions holds available options to configure a Recorder.
+type RecorderOptions struct {
+	// Mode specifies whether interactions are recorded or replayed.
+	// Default is ModeAuto.
+	Mode Mode
+
+	// ScrubHeaders specifies additional headers to remove from recorded
+	// interactions. Authorization, Proxy-Authorization, and cookie
+	// headers are always removed.
+	ScrubHeaders []string
+
+	// Transport specifies the mechanism by which requests are made
+	// when recording interactions.
+	// Default is http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+// Interaction is a recorded request and the response that was received for it.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request that was recorded by a Recorder.
+type RecordedRequest struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"`
+}
+
+// RecordedResponse is a response that was recorded by a Recorder.
+type RecordedResponse struct {
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"`
+}
+
+// Recorder is a http.RoundTripper that records interactions with the GitHub
+// API to a fixture file and replays them deterministically. A Recorder is
+// intended to be used as the Transport of api.ClientOptions.
+//
+// Requests are matched to recorded interactions by method and URL, and for
+// GraphQL requests by query and variables. Each recorded interaction is
+// replayed at most once, in the order that it was recorded.
+type Recorder struct {
+	path         string
+	mode         Mode
+	scrubHeaders []string
+	transport    http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     []bool
+}
+
+// NewRecorder returns a Recorder that uses the fixture file at path.
+// In replay mode the fixture file must exist.
+func NewRecorder(path string, opts RecorderOptions) (*Recorder, error) {
+	r := &Recorder{
+		path:         path,
+		mode:         opts.Mode,
+		scrubHeaders: append(append([]string{}, scrubbedHeaders...), opts.ScrubHeaders...),
+		transport:    opts.Transport,
+	}
+	if r.transport == nil {
+		r.transport = http.DefaultTransport
+	}
+
+	if r.mode == ModeAuto {
+		r.mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		}
+	}
+
+	if r.mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &r.interactions); err != nil {
+			return nil, fmt.Errorf("invalid fixture file %s: %w", path, err)
+		}
+		r.replayed = make([]bool, len(r.interactions))
+	}
+
+	return r, nil
+}
+
+// Mode returns whether the Recorder is recording or replaying interactions.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Interactions returns the interactions that have been recorded or loaded.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction{}, r.interactions...)
+}
+
+// RoundTrip replays the recorded response for req, or sends req
+// and records the interaction when recording.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, reqBody)
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: r.scrub(req.Header),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     r.scrub(resp.Header),
+		},
+	}
+	interaction.Request.Body, interaction.Request.BodyEncoding = encodeBody(reqBody)
+	interaction.Response.Body, interaction.Response.BodyEncoding = encodeBody(respBody)
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// Save writes the recorded interactions to the fixture file.
+// Save does nothing when replaying interactions.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0644)
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.replayed[i] || !matches(interaction.Request, req, body) {
+			continue
+		}
+		r.replayed[i] = true
+
+		respBody, err := decodeBody(interaction.Response.Body, interaction.Response.BodyEncoding)
+		if err != nil {
+			return nil, err
+		}
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(respBody)),
+			ContentLength: int64(len(respBody)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded interaction for %s %s in %s", req.Method, req.URL, r.path)
+}
+
+func (r *Recorder) scrub(h http.Header) http.Header {
+	h = h.Clone()
+	for _, k := range r.scrubHeaders {
+		h.Del(k)
+	}
+	if len(h) == 0 {
+		return nil
+	}
+	return h
+}
+
+func matches(recorded RecordedRequest, req *http.Request, body []byte) bool {
+	if !strings.EqualFold(recorded.Method, req.Method) || recorded.URL != req.URL.String() {
+		return false
+	}
+	if !isGraphQL(req) {
+		return true
+	}
+	recordedBody, err := decodeBody(recorded.Body, recorded.BodyEncoding)
+	if err != nil {
+		return false
+	}
+	return graphQLEqual(recordedBody, body)
+}
+
+func isGraphQL(req *http.Request) bool {
+	return req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/graphql")
+}
+
+// graphQLEqual compares GraphQL request bodies ignoring differences
+// in query whitespace and in the order of variables.
+func graphQLEqual(a, b []byte) bool {
+	bodyA, err := graphql.ParseRequest(a)
+	if err != nil {
+		return bytes.Equal(a, b)
+	}
+	bodyB, err := graphql.ParseRequest(b)
+	if err != nil {
+		return false
+	}
+	var variablesA, variablesB interface{}
+	_ = json.Unmarshal(bodyA.Variables, &variablesA)
+	_ = json.Unmarshal(bodyB.Variables, &variablesB)
+	return strings.Join(strings.Fields(bodyA.Query), " ") == strings.Join(strings.Fields(bodyB.Query), " ") &&
+		reflect.DeepEqual(variablesA, variablesB)
+}
+
+// readBody reads the body fully and replaces it with an equivalent reader.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	defer (*body).Close()
+	data, err := io.ReadAll(*body)
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+func encodeBody(data []byte) (string, string) {
+	if utf8.Valid(data) {
+		return string(data), ""
+	}
+	return base64.StdEncoding.EncodeToString(data), base64Encoding
+}
+
+func decodeBody(body, encoding string) ([]byte, error) {
+	switch encoding {
+	case "":
+		return []byte(body), nil
+	case base64Encoding:
+		return base64.StdEncoding.DecodeString(body)
+	default:
+		return nil, fmt.Errorf("unknown body encoding %q", encoding)
+	}
+}
@@ -0,0 +1,134 @@
+package apitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "fixtures", "recorder.json")
+
+	counter := 0
+	server := api.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		counter++
+		body := fmt.Sprintf(`{"counter": %d}`, counter)
+		if req.URL.Path == "/graphql" {
+			reqBody, _ := io.ReadAll(req.Body)
+			body = fmt.Sprintf(`{"data": {"counter": %d, "request": %q}}`, counter, reqBody)
+		}
+		return &http.Response{
+			StatusCode: 200,
+			Header: http.Header{
+				"Content-Type": []string{"application/json"},
+				"Set-Cookie":   []string{"session=secret"},
+			},
+			Body: io.NopCloser(bytes.NewBufferString(body)),
+		}, nil
+	})
+
+	run := func(rec *Recorder) []string {
+		opts := api.ClientOptions{
+			Host:         "github.com",
+			AuthToken:    "secret-token",
+			Transport:    rec,
+			LogIgnoreEnv: true,
+		}
+		restClient, err := api.NewRESTClient(opts)
+		assert.NoError(t, err)
+		graphqlClient, err := api.NewGraphQLClient(opts)
+		assert.NoError(t, err)
+
+		var results []string
+		var restResponse struct{ Counter int }
+		assert.NoError(t, restClient.Get("repos/cli/cli", &restResponse))
+		results = append(results, fmt.Sprint(restResponse.Counter))
+		assert.NoError(t, restClient.Get("repos/cli/cli", &restResponse))
+		results = append(results, fmt.Sprint(restResponse.Counter))
+
+		var graphqlResponse struct{ Counter int }
+		query := "query RepositoryInfo($owner: String!, $name: String!) { repository(owner: $owner, name: $name) { id } }"
+		variables := map[string]interface{}{"owner": "cli", "name": "go-gh"}
+		assert.NoError(t, graphqlClient.Do(query, variables, &graphqlResponse))
+		results = append(results, fmt.Sprint(graphqlResponse.Counter))
+		variables = map[string]interface{}{"owner": "cli", "name": "cli"}
+		assert.NoError(t, graphqlClient.Do(query, variables, &graphqlResponse))
+		results = append(results, fmt.Sprint(graphqlResponse.Counter))
+		return results
+	}
+
+	rec, err := NewRecorder(fixture, RecorderOptions{Transport: server})
+	assert.NoError(t, err)
+	assert.Equal(t, ModeRecord, rec.Mode())
+	assert.Equal(t, []string{"1", "2", "3", "4"}, run(rec))
+	assert.NoError(t, rec.Save())
+
+	data, err := os.ReadFile(fixture)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+	var interactions []Interaction
+	assert.NoError(t, json.Unmarshal(data, &interactions))
+	assert.Len(t, interactions, 4)
+	assert.Equal(t, "GET", interactions[0].Request.Method)
+	assert.Equal(t, "https://api.github.com/repos/cli/cli", interactions[0].Request.URL)
+	assert.Equal(t, "", interactions[0].Request.Header.Get("Authorization"))
+	assert.Equal(t, "go-gh", interactions[0].Request.Header.Get("User-Agent"))
+	assert.Equal(t, "", interactions[0].Response.Header.Get("Set-Cookie"))
+
+	// Replaying does not send any requests and returns the recorded responses in order.
+	rec, err = NewRecorder(fixture, RecorderOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, ModeReplay, rec.Mode())
+	assert.Equal(t, []string{"1", "2", "3", "4"}, run(rec))
+	assert.Equal(t, 4, counter)
+}
+
+func TestRecorderReplayUnmatched(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "recorder.json")
+	assert.NoError(t, os.WriteFile(fixture, []byte(`[
+  {
+    "request": {"method": "GET", "url": "https://api.github.com/user"},
+    "response": {"status_code": 200, "header": {"Content-Type": ["application/json"]}, "body": "{\"login\": \"monalisa\"}"}
+  }
+]`), 0644))
+
+	rec, err := NewRecorder(fixture, RecorderOptions{Mode: ModeReplay})
+	assert.NoError(t, err)
+	client, err := api.NewRESTClient(api.ClientOptions{
+		Host:         "github.com",
+		AuthToken:    "token",
+		Transport:    rec,
+		LogIgnoreEnv: true,
+	})
+	assert.NoError(t, err)
+
+	var user struct{ Login string }
+	assert.NoError(t, client.Get("user", &user))
+	assert.Equal(t, "monalisa", user.Login)
+
+	err = client.Get("user", &user)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no recorded interaction for GET https://api.github.com/user")
+}
+
+func TestRecorderReplayMissingFixture(t *testing.T) {
+	_, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), RecorderOptions{Mode: ModeReplay})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestRecorderBinaryBody(t *testing.T) {
+	body := []byte{0x50, 0x4b, 0x03, 0x04, 0xff, 0xfe}
+	encoded, encoding := encodeBody(body)
+	assert.Equal(t, base64Encoding, encoding)
+	decoded, err := decodeBody(encoded, encoding)
+	assert.NoError(t, err)
+	assert.Equal(t, body, decoded)
+}
@@ -0,0 +1,766 @@
+package apitest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/v2/internal/graphql"
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+const (
+	defaultPerPage   = 30
+	defaultRateLimit = 5000
+	maxPerPage       = 100
+)
+
+// User is a GitHub user as returned by the fake Server.
+type User struct {
+	Login string `json:"login"`
+}
+
+// Label is an issue label as returned by the fake Server.
+type Label struct {
+	Name string `json:"name"`
+}
+
+// Repository is a GitHub repository as returned by the fake Server.
+type Repository struct {
+	ID            int64  `json:"id"`
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	Owner         User   `json:"owner"`
+	Description   string `json:"description"`
+	Private       bool   `json:"private"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+// Issue is a GitHub issue as returned by the fake Server. Like the issues API,
+// the fake Server also returns pull requests as issues with PullRequest set.
+type Issue struct {
+	ID          int64             `json:"id"`
+	Number      int               `json:"number"`
+	Title       string            `json:"title"`
+	Body        string            `json:"body"`
+	State       string            `json:"state"`
+	User        User              `json:"user"`
+	Labels      []Label           `json:"labels"`
+	PullRequest *IssuePullRequest `json:"pull_request,omitempty"`
+}
+
+// IssuePullRequest links an issue to the pull request it represents.
+type IssuePullRequest struct {
+	URL string `json:"url"`
+}
+
+// PullRequestRef is the head or base of a pull request as returned by the fake Server.
+type PullRequestRef struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
+}
+
+// PullRequest is a GitHub pull request as returned by the fake Server.
+type PullRequest struct {
+	ID     int64          `json:"id"`
+	Number int            `json:"number"`
+	Title  string         `json:"title"`
+	Body   string         `json:"body"`
+	State  string         `json:"state"`
+	Draft  bool           `json:"draft"`
+	User   User           `json:"user"`
+	Head   PullRequestRef `json:"head"`
+	Base   PullRequestRef `json:"base"`
+}
+
+// Call is a request that was received by the fake Server.
+type Call struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   []byte
+
+	// OperationName is the name of the GraphQL operation for GraphQL requests.
+	OperationName string
+}
+
+// GraphQLResolver returns the data for a GraphQL operation given its variables.
+// A returned error is reported to the client as a GraphQL error.
+type GraphQLResolver func(variables map[string]interface{}) (interface{}, error)
+
+type route struct {
+	method   string
+	segments []string
+	handler  http.HandlerFunc
+}
+
+type repositoryData struct {
+	repo   Repository
+	issues []Issue
+	pulls  []PullRequest
+}
+
+type pathParamsKey struct{}
+
+// Server is a programmable in-process fake of the GitHub API. It serves REST
+// routes for repositories, issues, and pull requests from in-memory data, and
+// a GraphQL endpoint backed by resolvers registered with HandleGraphQL.
+//
+// Requests must be authenticated with the server's token, and responses carry
+// rate limit and pagination headers like the real API. Every request is
+// recorded so that tests can assert which calls were made.
+type Server struct {
+	token string
+
+	mu            sync.Mutex
+	nextID        int64
+	repos         map[string]*repositoryData
+	routes        []route
+	resolvers     map[string]GraphQLResolver
+	calls         []Call
+	rateLimit     int
+	rateRemaining int
+	rateReset     time.Time
+	listeners     []net.Listener
+}
+
+// NewServer returns a fake Server that accepts requests authenticated with token.
+func NewServer(token string) *Server {
+	s := &Server{
+		token:         token,
+		repos:         map[string]*repositoryData{},
+		resolvers:     map[string]GraphQLResolver{},
+		rateLimit:     defaultRateLimit,
+		rateRemaining: defaultRateLimit,
+		rateReset:     time.Now().Add(time.Hour),
+	}
+	s.routes = []route{
+		newRoute(http.MethodGet, "/repos/{owner}/{repo}", s.getRepository),
+		newRoute(http.MethodGet, "/repos/{owner}/{repo}/issues", s.listIssues),
+		newRoute(http.MethodPost, "/repos/{owner}/{repo}/issues", s.createIssue),
+		newRoute(http.MethodGet, "/repos/{owner}/{repo}/issues/{number}", s.getIssue),
+		newRoute(http.MethodPatch, "/repos/{owner}/{repo}/issues/{number}", s.updateIssue),
+		newRoute(http.MethodGet, "/repos/{owner}/{repo}/pulls", s.listPullRequests),
+		newRoute(http.MethodGet, "/repos/{owner}/{repo}/pulls/{number}", s.getPullRequest),
+	}
+	return s
+}
+
+// ClientOptions returns api.ClientOptions that route requests to the server
+// in-process and authenticate with the server's token.
+func (s *Server) ClientOptions() api.ClientOptions {
+	return api.ClientOptions{
+		Host:         "github.com",
+		AuthToken:    s.token,
+		Transport:    s.Transport(),
+		LogIgnoreEnv: true,
+	}
+}
+
+// Transport returns a http.RoundTripper that serves requests in-process,
+// suitable for use as the Transport of api.ClientOptions.
+func (s *Server) Transport() http.RoundTripper {
+	return api.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if err := req.Context().Err(); err != nil {
+			return nil, err
+		}
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		resp := rec.Result()
+		resp.Request = req
+		return resp, nil
+	})
+}
+
+// ListenUnix serves requests on a Unix domain socket at socketPath, suitable
+// for use as the UnixDomainSocket of api.ClientOptions. The socket is closed
+// when the server is closed.
+func (s *Server) ListenUnix(socketPath string) error {
+	l, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.listeners = append(s.listeners, l)
+	s.mu.Unlock()
+	go func() {
+		_ = http.Serve(l, s)
+	}()
+	return nil
+}
+
+// Close stops serving requests on any Unix domain sockets.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var err error
+	for _, l := range s.listeners {
+		if closeErr := l.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	s.listeners = nil
+	return err
+}
+
+// AddRepository adds a repository to the server.
+// Missing identifiers and defaults are filled in.
+func (s *Server) AddRepository(repo Repository) Repository {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if repo.ID == 0 {
+		repo.ID = s.newID()
+	}
+	if repo.FullName == "" {
+		repo.FullName = repo.Owner.Login + "/" + repo.Name
+	}
+	if repo.DefaultBranch == "" {
+		repo.DefaultBranch = "main"
+	}
+	s.repos[strings.ToLower(repo.FullName)] = &repositoryData{repo: repo}
+	return repo
+}
+
+// AddIssue adds an issue to the repository owner/repo, which is created if it
+// does not exist. The issue is numbered if no number is specified.
+func (s *Server) AddIssue(owner, repo string, issue Issue) Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data := s.repository(owner, repo)
+	if issue.ID == 0 {
+		issue.ID = s.newID()
+	}
+	if issue.Number == 0 {
+		issue.Number = data.nextNumber()
+	}
+	if issue.State == "" {
+		issue.State = "open"
+	}
+	data.issues = append(data.issues, issue)
+	return issue
+}
+
+// AddPullRequest adds a pull request to the repository owner/repo, which is created
+// if it does not exist. The pull request is numbered if no number is specified.
+func (s *Server) AddPullRequest(owner, repo string, pr PullRequest) PullRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data := s.repository(owner, repo)
+	if pr.ID == 0 {
+		pr.ID = s.newID()
+	}
+	if pr.Number == 0 {
+		pr.Number = data.nextNumber()
+	}
+	if pr.State == "" {
+		pr.State = "open"
+	}
+	if pr.Base.Ref == "" {
+		pr.Base.Ref = data.repo.DefaultBranch
+	}
+	data.pulls = append(data.pulls, pr)
+	return pr
+}
+
+// HandleFunc registers a handler for REST requests with the given method and path
+// pattern, such as "/repos/{owner}/{repo}/releases". Values of path parameters are
+// available to the handler via PathParam. Handlers registered with HandleFunc take
+// precedence over the built-in routes.
+func (s *Server) HandleFunc(method, pattern string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes = append([]route{newRoute(method, pattern, handler)}, s.routes...)
+}
+
+// HandleGraphQL registers a resolver for the named GraphQL operation.
+func (s *Server) HandleGraphQL(operationName string, resolver GraphQLResolver) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resolvers[operationName] = resolver
+}
+
+// SetRateLimit sets the rate limit reported by the server and the number of
+// requests remaining. Requests are rejected once no requests remain.
+func (s *Server) SetRateLimit(limit, remaining int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateLimit = limit
+	s.rateRemaining = remaining
+}
+
+// Calls returns the requests that have been received by the server.
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Call{}, s.calls...)
+}
+
+// Called reports whether the server has received a request with
+// the given method and path.
+func (s *Server) Called(method, path string) bool {
+	for _, c := range s.Calls() {
+		if strings.EqualFold(c.Method, method) && c.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// PathParam returns the value of the named path parameter for
+// requests handled by handlers registered with HandleFunc.
+func PathParam(r *http.Request, name string) string {
+	params, _ := r.Context().Value(pathParamsKey{}).(map[string]string)
+	return params[name]
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v3")
+	isGraphQL := r.URL.Path == "/graphql" || r.URL.Path == "/api/graphql"
+
+	body, _ := readBody(&r.Body)
+	call := Call{
+		Method: r.Method,
+		Path:   path,
+		Query:  r.URL.Query(),
+		Body:   body,
+	}
+
+	var variables map[string]interface{}
+	if isGraphQL {
+		call.Path = "/graphql"
+		if gqlRequest, err := graphql.ParseRequest(body); err == nil {
+			_, call.OperationName, _ = gqlRequest.Operation()
+			_ = json.Unmarshal(gqlRequest.Variables, &variables)
+		}
+	}
+
+	s.mu.Lock()
+	s.calls = append(s.calls, call)
+	rateLimited := s.writeRateLimitHeaders(w.Header(), isGraphQL)
+	s.mu.Unlock()
+
+	if !s.authorized(r) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"message": "Bad credentials"})
+		return
+	}
+	if rateLimited {
+		writeJSON(w, http.StatusForbidden, map[string]string{"message": "API rate limit exceeded"})
+		return
+	}
+
+	if isGraphQL {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		s.serveGraphQL(w, call.OperationName, variables)
+		return
+	}
+
+	s.mu.Lock()
+	routes := s.routes
+	s.mu.Unlock()
+	for _, rt := range routes {
+		params, ok := rt.match(r.Method, path)
+		if !ok {
+			continue
+		}
+		rt.handler(w, r.WithContext(context.WithValue(r.Context(), pathParamsKey{}, params)))
+		return
+	}
+
+	writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || (!strings.EqualFold(scheme, "token") && !strings.EqualFold(scheme, "bearer")) {
+		return false
+	}
+	return token == s.token
+}
+
+// writeRateLimitHeaders consumes a request from the rate limit and writes the rate limit headers.
+// Returns true if the rate limit has been exceeded.
+func (s *Server) writeRateLimitHeaders(h http.Header, isGraphQL bool) bool {
+	exceeded := s.rateRemaining <= 0
+	if !exceeded {
+		s.rateRemaining--
+	}
+	resource := "core"
+	if isGraphQL {
+		resource = "graphql"
+	}
+	h.Set("X-RateLimit-Limit", strconv.Itoa(s.rateLimit))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(s.rateRemaining))
+	h.Set("X-RateLimit-Used", strconv.Itoa(s.rateLimit-s.rateRemaining))
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(s.rateReset.Unix(), 10))
+	h.Set("X-RateLimit-Resource", resource)
+	return exceeded
+}
+
+func (s *Server) serveGraphQL(w http.ResponseWriter, operationName string, variables map[string]interface{}) {
+	s.mu.Lock()
+	resolver := s.resolvers[operationName]
+	s.mu.Unlock()
+	if resolver == nil {
+		writeGraphQLError(w, fmt.Sprintf("no resolver for operation %q", operationName))
+		return
+	}
+	data, err := resolver(variables)
+	if err != nil {
+		writeGraphQLError(w, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+}
+
+func (s *Server) getRepository(w http.ResponseWriter, r *http.Request) {
+	data := s.lookupRepository(r)
+	if data == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, data.repo)
+}
+
+func (s *Server) listIssues(w http.ResponseWriter, r *http.Request) {
+	data := s.lookupRepository(r)
+	if data == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+		return
+	}
+	s.mu.Lock()
+	issues := []Issue{}
+	for _, issue := range data.issues {
+		if matchesState(issue.State, r.URL.Query().Get("state")) {
+			issues = append(issues, issue)
+		}
+	}
+	for _, pr := range data.pulls {
+		if matchesState(pr.State, r.URL.Query().Get("state")) {
+			issues = append(issues, data.pullRequestIssue(pr))
+		}
+	}
+	s.mu.Unlock()
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Number < issues[j].Number })
+	writePage(w, r, issues)
+}
+
+func (s *Server) createIssue(w http.ResponseWriter, r *http.Request) {
+	data := s.lookupRepository(r)
+	if data == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+		return
+	}
+	var input struct {
+		Title  string   `json:"title"`
+		Body   string   `json:"body"`
+		Labels []string `json:"labels"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Title == "" {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"message": "Validation Failed",
+			"errors":  []map[string]string{{"resource": "Issue", "field": "title", "code": "missing_field"}},
+		})
+		return
+	}
+	issue := Issue{Title: input.Title, Body: input.Body}
+	for _, l := range input.Labels {
+		issue.Labels = append(issue.Labels, Label{Name: l})
+	}
+	issue = s.AddIssue(PathParam(r, "owner"), PathParam(r, "repo"), issue)
+	writeJSON(w, http.StatusCreated, issue)
+}
+
+func (s *Server) getIssue(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if issue := s.issue(r); issue != nil {
+		writeJSON(w, http.StatusOK, issue)
+		return
+	}
+	data := s.repos[strings.ToLower(PathParam(r, "owner")+"/"+PathParam(r, "repo"))]
+	if data != nil {
+		number, _ := strconv.Atoi(PathParam(r, "number"))
+		for _, pr := range data.pulls {
+			if pr.Number == number {
+				writeJSON(w, http.StatusOK, data.pullRequestIssue(pr))
+				return
+			}
+		}
+	}
+	writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+}
+
+func (s *Server) updateIssue(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Title *string `json:"title"`
+		Body  *string `json:"body"`
+		State *string `json:"state"`
+	}
+	decodeErr := json.NewDecoder(r.Body).Decode(&input)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue := s.issue(r)
+	if issue == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+		return
+	}
+	if decodeErr != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Problems parsing JSON"})
+		return
+	}
+	if input.Title != nil {
+		issue.Title = *input.Title
+	}
+	if input.Body != nil {
+		issue.Body = *input.Body
+	}
+	if input.State != nil {
+		issue.State = *input.State
+	}
+	writeJSON(w, http.StatusOK, issue)
+}
+
+func (s *Server) listPullRequests(w http.ResponseWriter, r *http.Request) {
+	data := s.lookupRepository(r)
+	if data == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+		return
+	}
+	s.mu.Lock()
+	pulls := []PullRequest{}
+	for _, pr := range data.pulls {
+		if matchesState(pr.State, r.URL.Query().Get("state")) {
+			pulls = append(pulls, pr)
+		}
+	}
+	s.mu.Unlock()
+	writePage(w, r, pulls)
+}
+
+func (s *Server) getPullRequest(w http.ResponseWriter, r *http.Request) {
+	data := s.lookupRepository(r)
+	number, _ := strconv.Atoi(PathParam(r, "number"))
+	if data != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, pr := range data.pulls {
+			if pr.Number == number {
+				writeJSON(w, http.StatusOK, pr)
+				return
+			}
+		}
+	}
+	writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+}
+
+func (s *Server) lookupRepository(r *http.Request) *repositoryData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.repos[strings.ToLower(PathParam(r, "owner")+"/"+PathParam(r, "repo"))]
+}
+
+// issue returns the issue addressed by the request, or nil. The pointer
+// is only valid while the caller holds s.mu.
+func (s *Server) issue(r *http.Request) *Issue {
+	data := s.repos[strings.ToLower(PathParam(r, "owner")+"/"+PathParam(r, "repo"))]
+	if data == nil {
+		return nil
+	}
+	number, _ := strconv.Atoi(PathParam(r, "number"))
+	for i := range data.issues {
+		if data.issues[i].Number == number {
+			return &data.issues[i]
+		}
+	}
+	return nil
+}
+
+// repository returns the data for owner/repo, creating it if necessary.
+// The caller must hold s.mu.
+func (s *Server) repository(owner, repo string) *repositoryData {
+	key := strings.ToLower(owner + "/" + repo)
+	data, ok := s.repos[key]
+	if !ok {
+		data = &repositoryData{repo: Repository{
+			ID:            s.newID(),
+			Name:          repo,
+			FullName:      owner + "/" + repo,
+			Owner:         User{Login: owner},
+			DefaultBranch: "main",
+		}}
+		s.repos[key] = data
+	}
+	return data
+}
+
+// newID returns a unique identifier. The caller must hold s.mu.
+func (s *Server) newID() int64 {
+	s.nextID++
+	return s.nextID
+}
+
+// pullRequestIssue returns pr as it is returned by the issues API.
+func (d *repositoryData) pullRequestIssue(pr PullRequest) Issue {
+	return Issue{
+		ID:          pr.ID,
+		Number:      pr.Number,
+		Title:       pr.Title,
+		Body:        pr.Body,
+		State:       pr.State,
+		User:        pr.User,
+		PullRequest: &IssuePullRequest{URL: fmt.Sprintf("https://api.github.com/repos/%s/pulls/%d", d.repo.FullName, pr.Number)},
+	}
+}
+
+// nextNumber returns the next number shared by issues and pull requests.
+func (d *repositoryData) nextNumber() int {
+	n := 0
+	for _, issue := range d.issues {
+		if issue.Number > n {
+			n = issue.Number
+		}
+	}
+	for _, pr := range d.pulls {
+		if pr.Number > n {
+			n = pr.Number
+		}
+	}
+	return n + 1
+}
+
+func newRoute(method, pattern string, handler http.HandlerFunc) route {
+	return route{
+		method:   method,
+		segments: strings.Split(strings.Trim(pattern, "/"), "/"),
+		handler:  handler,
+	}
+}
+
+func (rt route) match(method, path string) (map[string]string, bool) {
+	if !strings.EqualFold(rt.method, method) {
+		return nil, false
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, seg := range rt.segments {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			params[seg[1:len(seg)-1]] = segments[i]
+			continue
+		}
+		if seg != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func matchesState(state, filter string) bool {
+	switch filter {
+	case "all":
+		return true
+	case "":
+		return state == "open"
+	default:
+		return state == filter
+	}
+}
+
+// writePage writes the page of items requested by the page and per_page
+// query parameters along with a Link header for navigating between pages.
+func writePage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	query := r.URL.Query()
+	perPage, err := strconv.Atoi(query.Get("per_page"))
+	if err != nil || perPage <= 0 {
+		perPage = defaultPerPage
+	}
+	if perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+
+	lastPage := (len(items) + perPage - 1) / perPage
+	if lastPage == 0 {
+		lastPage = 1
+	}
+
+	start := (page - 1) * perPage
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + perPage
+	if end > len(items) {
+		end = len(items)
+	}
+
+	pageURL := func(n int) string {
+		u := *r.URL
+		if u.Host == "" {
+			u.Host = r.Host
+		}
+		if u.Scheme == "" {
+			u.Scheme = "https"
+		}
+		q := u.Query()
+		q.Set("page", strconv.Itoa(n))
+		q.Set("per_page", strconv.Itoa(perPage))
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+	links := map[string]int{}
+	if page < lastPage {
+		links["next"] = page + 1
+		links["last"] = lastPage
+	}
+	if page > 1 {
+		links["first"] = 1
+		links["prev"] = page - 1
+	}
+	rels := make([]string, 0, len(links))
+	for rel := range links {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+	var link []string
+	for _, rel := range rels {
+		link = append(link, fmt.Sprintf("<%s>; rel=%q", pageURL(links[rel]), rel))
+	}
+	if len(link) > 0 {
+		w.Header().Set("Link", strings.Join(link, ", "))
+	}
+
+	writeJSON(w, http.StatusOK, items[start:end])
+}
+
+func writeGraphQLError(w http.ResponseWriter, message string) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":   nil,
+		"errors": []map[string]string{{"message": message}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,201 @@
+package apitest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerREST(t *testing.T) {
+	s := NewServer("secret-token")
+	s.AddRepository(Repository{Name: "cli", Owner: User{Login: "cli"}})
+	for i := 1; i <= 5; i++ {
+		s.AddIssue("cli", "cli", Issue{Title: fmt.Sprintf("Issue %d", i)})
+	}
+	s.AddIssue("cli", "cli", Issue{Title: "Closed", State: "closed"})
+	s.AddPullRequest("cli", "cli", PullRequest{Title: "Fix bug", Head: PullRequestRef{Ref: "fix"}})
+
+	client, err := api.NewRESTClient(s.ClientOptions())
+	assert.NoError(t, err)
+
+	var repo Repository
+	assert.NoError(t, client.Get("repos/cli/cli", &repo))
+	assert.Equal(t, "cli/cli", repo.FullName)
+	assert.Equal(t, "main", repo.DefaultBranch)
+
+	resp, err := client.Request("GET", "repos/cli/cli/issues?per_page=2&page=2", nil)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "4998", resp.Header.Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "core", resp.Header.Get("X-RateLimit-Resource"))
+	assert.Equal(t, `<https://api.github.com/repos/cli/cli/issues?page=1&per_page=2>; rel="first", `+
+		`<https://api.github.com/repos/cli/cli/issues?page=3&per_page=2>; rel="last", `+
+		`<https://api.github.com/repos/cli/cli/issues?page=3&per_page=2>; rel="next", `+
+		`<https://api.github.com/repos/cli/cli/issues?page=1&per_page=2>; rel="prev"`, resp.Header.Get("Link"))
+
+	var issues []Issue
+	assert.NoError(t, client.Get("repos/cli/cli/issues?state=all", &issues))
+	assert.Len(t, issues, 7)
+	assert.Nil(t, issues[5].PullRequest)
+	assert.Equal(t, &IssuePullRequest{URL: "https://api.github.com/repos/cli/cli/pulls/7"}, issues[6].PullRequest)
+
+	var created Issue
+	assert.NoError(t, client.Post("repos/cli/cli/issues", bytes.NewBufferString(`{"title": "New", "labels": ["bug"]}`), &created))
+	assert.Equal(t, 8, created.Number)
+	assert.Equal(t, []Label{{Name: "bug"}}, created.Labels)
+
+	var updated Issue
+	assert.NoError(t, client.Patch("repos/cli/cli/issues/8", bytes.NewBufferString(`{"state": "closed"}`), &updated))
+	assert.Equal(t, "closed", updated.State)
+
+	var pr PullRequest
+	assert.NoError(t, client.Get("repos/cli/cli/pulls/7", &pr))
+	assert.Equal(t, "fix", pr.Head.Ref)
+	assert.Equal(t, "main", pr.Base.Ref)
+
+	err = client.Get("repos/cli/missing", &repo)
+	var httpErr *api.HTTPError
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, 404, httpErr.StatusCode)
+
+	assert.True(t, s.Called("POST", "/repos/cli/cli/issues"))
+	assert.False(t, s.Called("DELETE", "/repos/cli/cli/issues/8"))
+	calls := s.Calls()
+	assert.Len(t, calls, 7)
+	assert.Equal(t, "2", calls[1].Query.Get("page"))
+	assert.JSONEq(t, `{"state": "closed"}`, string(calls[4].Body))
+}
+
+func TestServerUpdateIssueConcurrently(t *testing.T) {
+	s := NewServer("secret-token")
+	s.AddIssue("cli", "cli", Issue{Title: "Issue"})
+
+	client, err := api.NewRESTClient(s.ClientOptions())
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			s.AddIssue("cli", "cli", Issue{Title: fmt.Sprintf("Issue %d", i)})
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			body := fmt.Sprintf(`{"title": "Updated %d"}`, i)
+			assert.NoError(t, client.Patch("repos/cli/cli/issues/1", bytes.NewBufferString(body), nil))
+		}(i)
+	}
+	wg.Wait()
+
+	assert.NoError(t, client.Patch("repos/cli/cli/issues/1", bytes.NewBufferString(`{"title": "Final"}`), nil))
+	var issue Issue
+	assert.NoError(t, client.Get("repos/cli/cli/issues/1", &issue))
+	assert.Equal(t, "Final", issue.Title)
+}
+
+func TestServerAuth(t *testing.T) {
+	s := NewServer("secret-token")
+	opts := s.ClientOptions()
+	opts.AuthToken = "wrong-token"
+	client, err := api.NewRESTClient(opts)
+	assert.NoError(t, err)
+
+	err = client.Get("repos/cli/cli", nil)
+	var httpErr *api.HTTPError
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, 401, httpErr.StatusCode)
+	assert.Equal(t, "Bad credentials", httpErr.Message)
+}
+
+func TestServerRateLimit(t *testing.T) {
+	s := NewServer("secret-token")
+	s.AddRepository(Repository{Name: "cli", Owner: User{Login: "cli"}})
+	s.SetRateLimit(60, 1)
+	client, err := api.NewRESTClient(s.ClientOptions())
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.Get("repos/cli/cli", nil))
+	err = client.Get("repos/cli/cli", nil)
+	var httpErr *api.HTTPError
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, 403, httpErr.StatusCode)
+	assert.Equal(t, "0", httpErr.Headers.Get("X-RateLimit-Remaining"))
+}
+
+func TestServerGraphQL(t *testing.T) {
+	s := NewServer("secret-token")
+	s.HandleGraphQL("RepositoryInfo", func(variables map[string]interface{}) (interface{}, error) {
+		if variables["name"] == "missing" {
+			return nil, errors.New("Could not resolve to a Repository")
+		}
+		return map[string]interface{}{
+			"repository": map[string]interface{}{"name": variables["name"]},
+		}, nil
+	})
+	client, err := api.NewGraphQLClient(s.ClientOptions())
+	assert.NoError(t, err)
+
+	query := "query RepositoryInfo($owner: String!, $name: String!) { repository(owner: $owner, name: $name) { name } }"
+	var response struct{ Repository struct{ Name string } }
+	assert.NoError(t, client.Do(query, map[string]interface{}{"owner": "cli", "name": "go-gh"}, &response))
+	assert.Equal(t, "go-gh", response.Repository.Name)
+
+	err = client.Do(query, map[string]interface{}{"owner": "cli", "name": "missing"}, &response)
+	var gqlErr *api.GraphQLError
+	assert.True(t, errors.As(err, &gqlErr))
+	assert.Contains(t, err.Error(), "Could not resolve to a Repository")
+
+	err = client.Do("query Viewer { viewer { login } }", nil, &response)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `no resolver for operation "Viewer"`)
+
+	calls := s.Calls()
+	assert.Len(t, calls, 3)
+	assert.Equal(t, "/graphql", calls[0].Path)
+	assert.Equal(t, "RepositoryInfo", calls[0].OperationName)
+}
+
+func TestServerHandleFunc(t *testing.T) {
+	s := NewServer("secret-token")
+	s.HandleFunc("GET", "/repos/{owner}/{repo}/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"tag_name": "v1.0.0", "repo": %q}`, PathParam(r, "owner")+"/"+PathParam(r, "repo"))
+	})
+	client, err := api.NewRESTClient(s.ClientOptions())
+	assert.NoError(t, err)
+
+	var release struct {
+		TagName string `json:"tag_name"`
+		Repo    string
+	}
+	assert.NoError(t, client.Get("repos/cli/go-gh/releases/latest", &release))
+	assert.Equal(t, "v1.0.0", release.TagName)
+	assert.Equal(t, "cli/go-gh", release.Repo)
+}
+
+func TestServerListenUnix(t *testing.T) {
+	s := NewServer("secret-token")
+	s.AddRepository(Repository{Name: "go-gh", Owner: User{Login: "cli"}})
+	socketPath := filepath.Join(t.TempDir(), "gh.sock")
+	assert.NoError(t, s.ListenUnix(socketPath))
+	defer s.Close()
+
+	client, err := api.NewRESTClient(api.ClientOptions{
+		Host:             "github.com",
+		AuthToken:        "secret-token",
+		UnixDomainSocket: socketPath,
+		LogIgnoreEnv:     true,
+	})
+	assert.NoError(t, err)
+
+	var repo Repository
+	assert.NoError(t, client.Get("repos/cli/go-gh", &repo))
+	assert.Equal(t, "cli/go-gh", repo.FullName)
+}
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBulkConcurrency = 4
+	defaultBulkMaxRetries  = 3
+	retryAfter             = "Retry-After"
+)
+
+// BulkOptions holds available options to configure a BulkExecutor.
+type BulkOptions struct {
+	// Concurrency is the maximum number of requests that are in flight at once.
+	// Default is 4.
+	Concurrency int
+
+	// MaxRetries is the number of times a request that was rejected because of
+	// a primary or secondary rate limit is retried once the limit has passed.
+	// A negative value disables retries.
+	// Default is 3.
+	MaxRetries int
+}
+
+// BulkRequest is a single REST or GraphQL request to run with a BulkExecutor.
+// Use NewRESTBulkRequest or NewGraphQLBulkRequest to create one.
+type BulkRequest struct {
+	do func(ctx context.Context, e *BulkExecutor) error
+}
+
+// BulkExecutor runs many API requests with a bounded number of workers.
+// Rate limit headers from every response are shared between the workers so
+// that once a rate limit is exhausted, or a secondary rate limit asks clients
+// to back off, no worker sends another request until the limit has passed.
+type BulkExecutor struct {
+	concurrency   int
+	graphQLClient *GraphQLClient
+	limiter       *rateLimiter
+	restClient    *RESTClient
+}
+
+// NewBulkExecutor builds an executor that sends requests using clients
+// configured with clientOpts.
+func NewBulkExecutor(clientOpts ClientOptions, opts BulkOptions) (*BulkExecutor, error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultBulkConcurrency
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = defaultBulkMaxRetries
+	} else if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+
+	limiter := newRateLimiter(opts.MaxRetries)
+	// Copy the middleware so that the caller's slice is never modified.
+	clientOpts.Middleware = append(append([]Middleware{}, clientOpts.Middleware...), Middleware{
+		Position: MiddlewareAfterCache,
+		Wrap:     limiter.wrap,
+	})
+
+	restClient, err := NewRESTClient(clientOpts)
+	if err != nil {
+		return nil, err
+	}
+	graphQLClient, err := NewGraphQLClient(clientOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BulkExecutor{
+		concurrency:   opts.Concurrency,
+		graphQLClient: graphQLClient,
+		limiter:       limiter,
+		restClient:    restClient,
+	}, nil
+}
+
+// NewRESTBulkRequest creates a request issuing a REST request with type specified
+// by method to the specified path with the specified body.
+// The response is populated into the response argument.
+func NewRESTBulkRequest(method string, path string, body []byte, response interface{}) BulkRequest {
+	return BulkRequest{
+		do: func(ctx context.Context, e *BulkExecutor) error {
+			var r io.Reader
+			if body != nil {
+				r = bytes.NewReader(body)
+			}
+			return e.restClient.DoWithContext(ctx, method, path, r, response)
+		},
+	}
+}
+
+// NewGraphQLBulkRequest creates a request executing a GraphQL query.
+// The response is populated into the response argument.
+func NewGraphQLBulkRequest(query string, variables map[string]interface{}, response interface{}) BulkRequest {
+	return BulkRequest{
+		do: func(ctx context.Context, e *BulkExecutor) error {
+			return e.graphQLClient.DoWithContext(ctx, query, variables, response)
+		},
+	}
+}
+
+// Run sends the requests and waits for all of them to finish.
+// The returned errors are in the same order as the requests, with a nil error
+// for each request that succeeded. When ctx is canceled, requests that have
+// not started yet are not sent and their errors are set to the context error.
+func (e *BulkExecutor) Run(ctx context.Context, requests []BulkRequest) []error {
+	errs := make([]error, len(requests))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(e.concurrency, len(requests)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = requests[i].do(ctx, e)
+			}
+		}()
+	}
+
+	for i := range requests {
+		if ctx.Err() == nil {
+			select {
+			case indexes <- i:
+				continue
+			case <-ctx.Done():
+			}
+		}
+		errs[i] = ctx.Err()
+	}
+	close(indexes)
+	wg.Wait()
+
+	return errs
+}
+
+// rateLimiter pauses requests while a rate limit is in effect and retries
+// requests that were rejected because of one.
+type rateLimiter struct {
+	maxRetries int
+	mu         sync.Mutex
+	now        func() time.Time
+	resumeAt   time.Time
+	sleep      func(ctx context.Context, d time.Duration) error
+}
+
+func newRateLimiter(maxRetries int) *rateLimiter {
+	return &rateLimiter{
+		maxRetries: maxRetries,
+		now:        time.Now,
+		sleep:      sleepContext,
+	}
+}
+
+func (l *rateLimiter) wrap(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		for attempt := 0; ; attempt++ {
+			if err := l.wait(req.Context()); err != nil {
+				return nil, err
+			}
+
+			r := req
+			if attempt > 0 {
+				r = req.Clone(req.Context())
+				if req.GetBody != nil {
+					body, err := req.GetBody()
+					if err != nil {
+						return nil, err
+					}
+					r.Body = body
+				}
+			}
+
+			resp, err := next.RoundTrip(r)
+			if err != nil {
+				return nil, err
+			}
+
+			limited := l.observe(resp)
+			canRetry := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+			if !limited || attempt >= l.maxRetries || !canRetry {
+				return resp, nil
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+	})
+}
+
+// wait blocks until no rate limit is in effect or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		d := l.resumeAt.Sub(l.now())
+		l.mu.Unlock()
+		if d <= 0 {
+			return ctx.Err()
+		}
+		if err := l.sleep(ctx, d); err != nil {
+			return err
+		}
+	}
+}
+
+// observe records the rate limit state from the response headers and
+// reports whether the request was rejected because of a rate limit.
+func (l *rateLimiter) observe(resp *http.Response) bool {
+	var resumeAt time.Time
+	if resp.Header.Get(rateLimitRemaining) == "0" {
+		if v, err := strconv.ParseInt(resp.Header.Get(rateLimitReset), 10, 64); err == nil {
+			resumeAt = time.Unix(v, 0)
+		}
+	}
+
+	limited := false
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		if v, err := strconv.Atoi(resp.Header.Get(retryAfter)); err == nil {
+			limited = true
+			if t := l.now().Add(time.Duration(v) * time.Second); t.After(resumeAt) {
+				resumeAt = t
+			}
+		} else if !resumeAt.IsZero() {
+			limited = true
+		}
+	}
+
+	if !resumeAt.IsZero() {
+		l.mu.Lock()
+		if resumeAt.After(l.resumeAt) {
+			l.resumeAt = resumeAt
+		}
+		l.mu.Unlock()
+	}
+	return limited
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBulkExecutor(t *testing.T, opts BulkOptions, roundTrip func(*http.Request) (*http.Response, error)) *BulkExecutor {
+	t.Helper()
+	e, err := NewBulkExecutor(ClientOptions{
+		Host:         "github.com",
+		AuthToken:    "token",
+		Transport:    tripper{roundTrip: roundTrip},
+		LogIgnoreEnv: true,
+	}, opts)
+	assert.NoError(t, err)
+	return e
+}
+
+func bulkResponse(req *http.Request, status int, header http.Header, body string) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Type", "application/json")
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Request:    req,
+	}
+}
+
+func TestBulkExecutor(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	e := newTestBulkExecutor(t, BulkOptions{Concurrency: 3}, func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		switch req.URL.Path {
+		case "/graphql":
+			return bulkResponse(req, 200, nil, `{"data": {"viewer": {"login": "monalisa"}}}`), nil
+		case "/repos/cli/missing":
+			return bulkResponse(req, 404, nil, `{"message": "Not Found"}`), nil
+		}
+		return bulkResponse(req, 200, nil, fmt.Sprintf(`{"path": %q}`, req.URL.Path)), nil
+	})
+
+	type result struct{ Path string }
+	results := make([]result, 10)
+	var requests []BulkRequest
+	for i := range results {
+		requests = append(requests, NewRESTBulkRequest("GET", "repos/cli/cli/issues/"+strconv.Itoa(i), nil, &results[i]))
+	}
+	var missing result
+	requests = append(requests, NewRESTBulkRequest("GET", "repos/cli/missing", nil, &missing))
+	var viewer struct{ Viewer struct{ Login string } }
+	requests = append(requests, NewGraphQLBulkRequest("query { viewer { login } }", nil, &viewer))
+
+	errs := e.Run(context.Background(), requests)
+	assert.Len(t, errs, 12)
+	for i := range results {
+		assert.NoError(t, errs[i])
+		assert.Equal(t, "/repos/cli/cli/issues/"+strconv.Itoa(i), results[i].Path)
+	}
+	assert.EqualError(t, errs[10], "HTTP 404: Not Found (https://api.github.com/repos/cli/missing)")
+	assert.NoError(t, errs[11])
+	assert.Equal(t, "monalisa", viewer.Viewer.Login)
+	assert.LessOrEqual(t, maxInFlight, 3)
+	assert.Greater(t, maxInFlight, 1)
+}
+
+func TestBulkExecutorRateLimit(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	var mu sync.Mutex
+	var calls []string
+	var sleeps []time.Duration
+	e := newTestBulkExecutor(t, BulkOptions{Concurrency: 1}, func(req *http.Request) (*http.Response, error) {
+		var body []byte
+		if req.Body != nil {
+			body, _ = io.ReadAll(req.Body)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, req.URL.Path+" "+string(body))
+		switch len(calls) {
+		case 1:
+			// Secondary rate limit.
+			return bulkResponse(req, 403, http.Header{"Retry-After": []string{"60"}}, `{"message": "You have exceeded a secondary rate limit."}`), nil
+		case 2:
+			// Primary rate limit is exhausted by this request.
+			return bulkResponse(req, 200, http.Header{
+				"X-Ratelimit-Remaining": []string{"0"},
+				"X-Ratelimit-Reset":     []string{strconv.FormatInt(now.Add(10*time.Minute).Unix(), 10)},
+			}, `{}`), nil
+		}
+		return bulkResponse(req, 200, nil, `{}`), nil
+	})
+	e.limiter.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	e.limiter.sleep = func(ctx context.Context, d time.Duration) error {
+		mu.Lock()
+		defer mu.Unlock()
+		sleeps = append(sleeps, d)
+		now = now.Add(d)
+		return nil
+	}
+
+	errs := e.Run(context.Background(), []BulkRequest{
+		NewRESTBulkRequest("POST", "repos/cli/cli/issues", []byte(`{"title": "a"}`), nil),
+		NewRESTBulkRequest("GET", "repos/cli/cli", nil, nil),
+	})
+	assert.Equal(t, []error{nil, nil}, errs)
+	assert.Equal(t, []string{
+		`/repos/cli/cli/issues {"title": "a"}`,
+		`/repos/cli/cli/issues {"title": "a"}`,
+		`/repos/cli/cli `,
+	}, calls)
+	assert.Equal(t, []time.Duration{time.Minute, 10 * time.Minute}, sleeps)
+}
+
+func TestBulkExecutorMaxRetries(t *testing.T) {
+	calls := 0
+	e := newTestBulkExecutor(t, BulkOptions{MaxRetries: -1}, func(req *http.Request) (*http.Response, error) {
+		calls++
+		return bulkResponse(req, 429, http.Header{"Retry-After": []string{"1"}}, `{"message": "Too many requests"}`), nil
+	})
+
+	errs := e.Run(context.Background(), []BulkRequest{NewRESTBulkRequest("GET", "user", nil, nil)})
+	assert.EqualError(t, errs[0], "HTTP 429: Too many requests (https://api.github.com/user)")
+	assert.Equal(t, 1, calls)
+}
+
+func TestBulkExecutorCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	e := newTestBulkExecutor(t, BulkOptions{Concurrency: 1}, func(req *http.Request) (*http.Response, error) {
+		calls++
+		cancel()
+		return bulkResponse(req, 200, nil, `{}`), nil
+	})
+
+	errs := e.Run(ctx, []BulkRequest{
+		NewRESTBulkRequest("GET", "user", nil, nil),
+		NewRESTBulkRequest("GET", "user", nil, nil),
+		NewRESTBulkRequest("GET", "user", nil, nil),
+	})
+	assert.Equal(t, 1, calls)
+	assert.Len(t, errs, 3)
+	assert.ErrorIs(t, errs[1], context.Canceled)
+	assert.ErrorIs(t, errs[2], context.Canceled)
+}
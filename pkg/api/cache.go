@@ -58,6 +58,9 @@ func cacheKey(req *http.Request) (string, error) {
 	fmt.Fprintf(h, "%s:", req.URL.String())
 	fmt.Fprintf(h, "%s:", req.Header.Get("Accept"))
 	fmt.Fprintf(h, "%s:", req.Header.Get("Authorization"))
+	if r := req.Header.Get("Range"); r != "" {
+		fmt.Fprintf(h, "%s:", r)
+	}
 
 	if req.Body != nil {
 		var bodyCopy io.ReadCloser
@@ -105,6 +108,7 @@ func (crt cacheRoundTripper) RoundTrip(req *http.Request) (*http.Response, error
 	if keyErr == nil {
 		if res, err := crt.fs.read(key); err == nil {
 			res.Request = req
+			markCacheHit(req)
 			return res, nil
 		}
 	}
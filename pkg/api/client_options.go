@@ -17,6 +17,12 @@ type ClientOptions struct {
 	// to authenticate against API endpoints.
 	AuthToken string
 
+	// CACertFile is the path to a file of PEM encoded certificates that are trusted
+	// in addition to the system certificate pool when verifying API servers.
+	// Default is the value of the http_ca_cert key for the host in the gh
+	// configuration, or only trusting the system certificate pool.
+	CACertFile string
+
 	// CacheDir is the directory to use for cached API requests.
 	// Default is the same directory that gh uses for caching.
 	CacheDir string
@@ -25,6 +31,21 @@ type ClientOptions struct {
 	// Default is 24 hours.
 	CacheTTL time.Duration
 
+	// ClientCertFile and ClientKeyFile are the paths to a PEM encoded certificate
+	// and private key presented to API servers that require client certificates.
+	// Default is the values of the http_client_cert and http_client_key keys for
+	// the host in the gh configuration, or no client certificate.
+	ClientCertFile string
+	ClientKeyFile  string
+
+	// DeduplicateRequests enables coalescing concurrent identical GET and HEAD
+	// requests and GraphQL queries into a single API request whose response is
+	// shared by every caller. GraphQL mutations and other requests are always
+	// sent. Shared response bodies are read in full before they are returned,
+	// including the bodies streamed by RESTClient.Stream.
+	// Default is sending every request.
+	DeduplicateRequests bool
+
 	// EnableCache specifies if API requests will be cached or not.
 	// Default is no caching.
 	EnableCache bool
@@ -37,6 +58,12 @@ type ClientOptions struct {
 	// Host is the default host that API requests will be sent to.
 	Host string
 
+	// Instrumenter receives events for every API request, such as timings, response
+	// sizes, cache status, and rate limit information. It can be used to implement
+	// tracing and metrics.
+	// Default is no instrumentation.
+	Instrumenter Instrumenter
+
 	// Log specifies a writer to write API request logs to. Default is to respect the GH_DEBUG environment
 	// variable, and no logging otherwise.
 	Log io.Writer
@@ -49,10 +76,36 @@ type ClientOptions struct {
 	// Default is no coloring.
 	LogColorize bool
 
-	// LogVerboseHTTP enables logging HTTP headers and bodies to Log.
+	// LogJSON enables writing a single structured JSON record per request to Log instead
+	// of human-readable output. Records include timings, sizes, cache status, and the
+	// GraphQL operation name. Setting GH_DEBUG to a value containing "json" enables this.
+	// Default is human-readable output.
+	LogJSON bool
+
+	// LogRedactKeys specifies additional header names and body field names whose values
+	// are masked when logging. Authorization headers, cookies, and well known sensitive
+	// JSON, form, and GraphQL variable fields such as client_secret are always masked.
+	// Default is no additional keys.
+	LogRedactKeys []string
+
+	// LogVerboseHTTP enables logging HTTP headers and bodies to Log. When LogJSON
+	// is set only headers are logged, with credentials redacted.
 	// Default is only logging request URLs and response statuses.
 	LogVerboseHTTP bool
 
+	// Middleware specifies additional round trippers to insert into the API request
+	// transport chain. Each middleware is inserted at its Position, and middleware
+	// sharing a position see requests in the order they are specified.
+	// Default is no middleware.
+	Middleware []Middleware
+
+	// Proxy is the URL of the proxy that API requests are sent through. It takes
+	// precedence over the HTTPS_PROXY, HTTP_PROXY, and NO_PROXY environment variables.
+	// Proxy is not used when requests are routed through UnixDomainSocket.
+	// Default is the value of the http_proxy key for the host in the gh
+	// configuration, or respecting the proxy environment variables.
+	Proxy string
+
 	// SkipDefaultHeaders disables setting of the default headers.
 	SkipDefaultHeaders bool
 
@@ -60,6 +113,12 @@ type ClientOptions struct {
 	// Default is no timeout.
 	Timeout time.Duration
 
+	// TLSMinVersion is the minimum TLS version, such as "1.2" or "1.3", that
+	// is accepted when connecting to API servers.
+	// Default is the value of the http_tls_min_version key for the host in the
+	// gh configuration, or the Go default.
+	TLSMinVersion string
+
 	// Transport specifies the mechanism by which individual API requests are made.
 	// If both Transport and UnixDomainSocket are specified then Transport takes
 	// precedence. Due to this behavior any value set for Transport needs to manually
@@ -73,6 +132,14 @@ type ClientOptions struct {
 	// request transport chain.
 	// Default is no socket address.
 	UnixDomainSocket string
+
+	// UnixDomainSockets maps hosts to the Unix domain socket addresses by which
+	// API requests to them will be routed. Requests to a host or any of its
+	// subdomains, such as api.github.com for github.com, use the host's socket,
+	// and requests to other hosts fall back to UnixDomainSocket if specified.
+	// Transport takes precedence over UnixDomainSockets.
+	// Default is the http_unix_socket value of each host in the gh configuration.
+	UnixDomainSockets map[string]string
 }
 
 func optionsNeedResolution(opts ClientOptions) bool {
@@ -100,7 +167,11 @@ func resolveOptions(opts ClientOptions) (ClientOptions, error) {
 		}
 	}
 	if opts.UnixDomainSocket == "" && cfg != nil {
-		opts.UnixDomainSocket, _ = cfg.Get([]string{"http_unix_socket"})
+		opts.UnixDomainSocket, _ = cfg.Get([]string{unixSocketKey})
+	}
+	if opts.UnixDomainSockets == nil && cfg != nil {
+		opts.UnixDomainSockets = hostUnixDomainSockets(cfg)
 	}
+	opts = resolveTransportOptions(opts, cfg)
 	return opts, nil
 }
@@ -16,17 +16,20 @@ func TestResolveOptions(t *testing.T) {
 		wantAuthToken string
 		wantHost      string
 		wantSocket    string
+		wantSockets   map[string]string
 	}{
 		{
 			name: "honors consumer provided ClientOptions",
 			opts: ClientOptions{
-				Host:             "test.com",
-				AuthToken:        "token_from_opts",
-				UnixDomainSocket: "socket_from_opts",
+				Host:              "test.com",
+				AuthToken:         "token_from_opts",
+				UnixDomainSocket:  "socket_from_opts",
+				UnixDomainSockets: map[string]string{"test.com": "test_socket_from_opts"},
 			},
 			wantAuthToken: "token_from_opts",
 			wantHost:      "test.com",
 			wantSocket:    "socket_from_opts",
+			wantSockets:   map[string]string{"test.com": "test_socket_from_opts"},
 		},
 		{
 			name:          "uses config values if there are no consumer provided ClientOptions",
@@ -34,6 +37,7 @@ func TestResolveOptions(t *testing.T) {
 			wantAuthToken: "token",
 			wantHost:      "github.com",
 			wantSocket:    "socket",
+			wantSockets:   map[string]string{"ghe.example.com": "ghe_socket"},
 		},
 	}
 
@@ -44,6 +48,7 @@ func TestResolveOptions(t *testing.T) {
 			assert.Equal(t, tt.wantHost, opts.Host)
 			assert.Equal(t, tt.wantAuthToken, opts.AuthToken)
 			assert.Equal(t, tt.wantSocket, opts.UnixDomainSocket)
+			assert.Equal(t, tt.wantSockets, opts.UnixDomainSockets)
 		})
 	}
 }
@@ -167,5 +172,9 @@ hosts:
     user: user1
     oauth_token: token
     git_protocol: ssh
+  ghe.example.com:
+    user: user2
+    oauth_token: ghe_token
+    http_unix_socket: ghe_socket
 `
 }
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh/v2/internal/graphql"
+)
+
+// dedupeRoundTripper coalesces concurrent identical read-only requests into
+// a single call to the next http.RoundTripper. The first request for a key
+// is sent and every request for the same key that arrives while it is in
+// flight receives a copy of its response.
+type dedupeRoundTripper struct {
+	calls map[string]*dedupeCall
+	mu    sync.Mutex
+	rt    http.RoundTripper
+}
+
+type dedupeCall struct {
+	body []byte
+	done chan struct{}
+	err  error
+	res  *http.Response
+}
+
+// dedupeWait is called when a request starts waiting for an identical
+// request in flight. It is stubbed by tests to synchronize with waiters.
+var dedupeWait = func() {}
+
+func newDedupeRoundTripper(rt http.RoundTripper) http.RoundTripper {
+	return &dedupeRoundTripper{
+		calls: map[string]*dedupeCall{},
+		rt:    rt,
+	}
+}
+
+func (drt *dedupeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isDeduplicableRequest(req) {
+		return drt.rt.RoundTrip(req)
+	}
+	key, err := cacheKey(req)
+	if err != nil {
+		return drt.rt.RoundTrip(req)
+	}
+
+	drt.mu.Lock()
+	if c, ok := drt.calls[key]; ok {
+		drt.mu.Unlock()
+		dedupeWait()
+		select {
+		case <-c.done:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		// If the request being waited on was canceled, this request is
+		// still wanted and is sent on its own.
+		if isContextError(c.err) && req.Context().Err() == nil {
+			return drt.RoundTrip(req)
+		}
+		return c.response(req)
+	}
+	c := &dedupeCall{done: make(chan struct{})}
+	drt.calls[key] = c
+	drt.mu.Unlock()
+
+	c.res, c.err = drt.rt.RoundTrip(req)
+	if c.err == nil {
+		// The body is read in full so that it can be shared with every waiter.
+		c.body, c.err = io.ReadAll(c.res.Body)
+		c.res.Body.Close()
+	}
+
+	drt.mu.Lock()
+	delete(drt.calls, key)
+	drt.mu.Unlock()
+	close(c.done)
+
+	return c.response(req)
+}
+
+// isDeduplicableRequest reports whether req is free of side effects, so that
+// concurrent identical requests can share a single response. These are GET
+// and HEAD requests and GraphQL query operations; GraphQL mutations and
+// subscriptions are always sent.
+func isDeduplicableRequest(req *http.Request) bool {
+	if strings.EqualFold(req.Method, http.MethodGet) || strings.EqualFold(req.Method, http.MethodHead) {
+		return true
+	}
+	if !isCacheableRequest(req) || req.Body == nil {
+		return false
+	}
+	var bodyCopy io.ReadCloser
+	req.Body, bodyCopy = copyStream(req.Body)
+	defer bodyCopy.Close()
+	data, err := io.ReadAll(bodyCopy)
+	if err != nil {
+		return false
+	}
+	body, err := graphql.ParseRequest(data)
+	if err != nil {
+		return false
+	}
+	kind, _, ok := body.Operation()
+	return ok && kind == graphql.Query
+}
+
+// response returns a copy of the shared response for req.
+func (c *dedupeCall) response(req *http.Request) (*http.Response, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	res := *c.res
+	res.Header = c.res.Header.Clone()
+	res.Body = io.NopCloser(bytes.NewReader(c.body))
+	res.Request = req
+	return &res, nil
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
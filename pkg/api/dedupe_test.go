@@ -0,0 +1,244 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeduplicateRequests(t *testing.T) {
+	waiting := stubDedupeWait(t)
+	var calls int32
+	entered := make(chan struct{}, 10)
+	release := make(chan struct{})
+	fakeHTTP := tripper{
+		roundTrip: func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&calls, 1)
+			entered <- struct{}{}
+			<-release
+			return &http.Response{
+				StatusCode: 200,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(bytes.NewBufferString(`{"path": "` + req.URL.Path + `"}`)),
+				Request:    req,
+			}, nil
+		},
+	}
+
+	client, err := NewHTTPClient(ClientOptions{
+		Host:                "github.com",
+		AuthToken:           "token",
+		Transport:           fakeHTTP,
+		DeduplicateRequests: true,
+		LogIgnoreEnv:        true,
+	})
+	assert.NoError(t, err)
+
+	get := func(url string) string {
+		res, err := client.Get(url)
+		if err != nil {
+			return err.Error()
+		}
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		return string(body)
+	}
+
+	var wg sync.WaitGroup
+	results := make([]string, 5)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0] = get("https://api.github.com/repos/cli/cli")
+	}()
+	<-entered
+	for i := 1; i < len(results); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = get("https://api.github.com/repos/cli/cli")
+		}(i)
+	}
+
+	// A request for another URL is not coalesced.
+	other := make(chan string)
+	go func() {
+		other <- get("https://api.github.com/repos/cli/go-gh")
+	}()
+	<-entered
+
+	for i := 1; i < len(results); i++ {
+		<-waiting
+	}
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, `{"path": "/repos/cli/go-gh"}`, <-other)
+	for _, result := range results {
+		assert.Equal(t, `{"path": "/repos/cli/cli"}`, result)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// Requests that are not cacheable are always sent.
+	for i := 0; i < 2; i++ {
+		res, err := client.Post("https://api.github.com/repos/cli/cli/issues", "application/json", bytes.NewBufferString(`{}`))
+		assert.NoError(t, err)
+		res.Body.Close()
+	}
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+}
+
+func TestDeduplicateGraphQLRequests(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantCalls int32
+	}{
+		{
+			name:      "queries are coalesced",
+			query:     `query Viewer { viewer { login } }`,
+			wantCalls: 1,
+		},
+		{
+			name:      "mutations are each sent",
+			query:     `mutation Star { addStar(input: {starrableId: \"R_1\"}) { clientMutationId } }`,
+			wantCalls: 3,
+		},
+		{
+			name:      "unparsable queries are each sent",
+			query:     `mutation {`,
+			wantCalls: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waiting := stubDedupeWait(t)
+			var calls int32
+			entered := make(chan struct{}, 3)
+			release := make(chan struct{})
+			fakeHTTP := tripper{
+				roundTrip: func(req *http.Request) (*http.Response, error) {
+					atomic.AddInt32(&calls, 1)
+					entered <- struct{}{}
+					<-release
+					return &http.Response{
+						StatusCode: 200,
+						Header:     http.Header{"Content-Type": []string{"application/json"}},
+						Body:       io.NopCloser(bytes.NewBufferString(`{"data": {}}`)),
+						Request:    req,
+					}, nil
+				},
+			}
+
+			client, err := NewHTTPClient(ClientOptions{
+				Host:                "github.com",
+				AuthToken:           "token",
+				Transport:           fakeHTTP,
+				DeduplicateRequests: true,
+				LogIgnoreEnv:        true,
+			})
+			assert.NoError(t, err)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 3; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					body := `{"query": "` + tt.query + `"}`
+					res, err := client.Post("https://api.github.com/graphql", "application/json", bytes.NewBufferString(body))
+					if assert.NoError(t, err) {
+						res.Body.Close()
+					}
+				}()
+			}
+
+			// Every request is either sent or waiting for one that was sent.
+			for i := int32(0); i < tt.wantCalls; i++ {
+				<-entered
+			}
+			for i := tt.wantCalls; i < 3; i++ {
+				<-waiting
+			}
+			close(release)
+			wg.Wait()
+
+			assert.Equal(t, tt.wantCalls, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestDeduplicateRequestsCanceled(t *testing.T) {
+	waiting := stubDedupeWait(t)
+	var calls int32
+	entered := make(chan struct{}, 10)
+	release := make(chan struct{})
+	drt := newDedupeRoundTripper(tripper{
+		roundTrip: func(req *http.Request) (*http.Response, error) {
+			if atomic.AddInt32(&calls, 1) > 1 {
+				return &http.Response{StatusCode: 200, Header: http.Header{}, Body: io.NopCloser(bytes.NewBufferString("ok"))}, nil
+			}
+			entered <- struct{}{}
+			select {
+			case <-release:
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			}
+			return nil, req.Context().Err()
+		},
+	})
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderReq, _ := http.NewRequestWithContext(leaderCtx, "GET", "https://api.github.com/user", nil)
+	leaderErr := make(chan error)
+	go func() {
+		_, err := drt.RoundTrip(leaderReq)
+		leaderErr <- err
+	}()
+	<-entered
+
+	// A waiter whose own context is canceled stops waiting.
+	waiterCtx, cancelWaiter := context.WithCancel(context.Background())
+	cancelWaiter()
+	waiterReq, _ := http.NewRequestWithContext(waiterCtx, "GET", "https://api.github.com/user", nil)
+	_, err := drt.RoundTrip(waiterReq)
+	assert.ErrorIs(t, err, context.Canceled)
+	<-waiting
+
+	// A waiter is sent on its own when the request it waits on is canceled.
+	waiterReq, _ = http.NewRequest("GET", "https://api.github.com/user", nil)
+	waiterRes := make(chan *http.Response)
+	go func() {
+		res, _ := drt.RoundTrip(waiterReq)
+		waiterRes <- res
+	}()
+	<-waiting
+	cancelLeader()
+
+	assert.ErrorIs(t, <-leaderErr, context.Canceled)
+	res := <-waiterRes
+	if assert.NotNil(t, res) {
+		body, _ := io.ReadAll(res.Body)
+		assert.Equal(t, "ok", string(body))
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+// stubDedupeWait returns a channel that receives a value each time a request
+// starts waiting for an identical request in flight.
+func stubDedupeWait(t *testing.T) <-chan struct{} {
+	t.Helper()
+	waiting := make(chan struct{}, 10)
+	old := dedupeWait
+	dedupeWait = func() { waiting <- struct{}{} }
+	t.Cleanup(func() {
+		dedupeWait = old
+	})
+	return waiting
+}
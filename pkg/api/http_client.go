@@ -3,7 +3,6 @@ package api
 import (
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 	"os"
 	"regexp"
@@ -32,6 +31,7 @@ const (
 )
 
 var jsonTypeRE = regexp.MustCompile(`[/+]json($|;)`)
+var textTypeRE = regexp.MustCompile(`(?i)^\s*text/`)
 
 func DefaultHTTPClient() (*http.Client, error) {
 	return NewHTTPClient(ClientOptions{})
@@ -55,18 +55,40 @@ func NewHTTPClient(opts ClientOptions) (*http.Client, error) {
 		}
 	}
 
-	transport := http.DefaultTransport
-
-	if opts.UnixDomainSocket != "" {
-		transport = newUnixDomainSocketRoundTripper(opts.UnixDomainSocket)
+	if opts.Log == nil && !opts.LogIgnoreEnv {
+		ghDebug := os.Getenv("GH_DEBUG")
+		switch ghDebug {
+		case "", "0", "false", "no":
+			// no logging
+		default:
+			opts.Log = os.Stderr
+			opts.LogColorize = !term.IsColorDisabled() && term.IsTerminal(os.Stderr)
+			opts.LogVerboseHTTP = strings.Contains(ghDebug, "api")
+			opts.LogJSON = strings.Contains(ghDebug, "json")
+		}
 	}
 
+	var transport http.RoundTripper
 	if opts.Transport != nil {
 		transport = opts.Transport
+	} else {
+		var err error
+		transport, err = newBaseTransport(opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Instrumenter != nil || (opts.Log != nil && opts.LogJSON) {
+		transport = attemptRoundTripper{rt: transport}
 	}
 
+	transport = applyMiddleware(transport, opts.Middleware, MiddlewareTransport)
+
 	transport = newSanitizerRoundTripper(transport)
 
+	transport = applyMiddleware(transport, opts.Middleware, MiddlewareAfterCache)
+
 	if opts.CacheDir == "" {
 		opts.CacheDir = config.CacheDir()
 	}
@@ -76,37 +98,45 @@ func NewHTTPClient(opts ClientOptions) (*http.Client, error) {
 	c := cache{dir: opts.CacheDir, ttl: opts.CacheTTL}
 	transport = c.RoundTripper(transport)
 
-	if opts.Log == nil && !opts.LogIgnoreEnv {
-		ghDebug := os.Getenv("GH_DEBUG")
-		switch ghDebug {
-		case "", "0", "false", "no":
-			// no logging
-		default:
-			opts.Log = os.Stderr
-			opts.LogColorize = !term.IsColorDisabled() && term.IsTerminal(os.Stderr)
-			opts.LogVerboseHTTP = strings.Contains(ghDebug, "api")
-		}
+	if opts.DeduplicateRequests {
+		transport = newDedupeRoundTripper(transport)
 	}
 
+	transport = applyMiddleware(transport, opts.Middleware, MiddlewareBeforeCache)
+
 	if opts.Log != nil {
-		logger := &httpretty.Logger{
-			Time:            true,
-			TLS:             false,
-			Colors:          opts.LogColorize,
-			RequestHeader:   opts.LogVerboseHTTP,
-			RequestBody:     opts.LogVerboseHTTP,
-			ResponseHeader:  opts.LogVerboseHTTP,
-			ResponseBody:    opts.LogVerboseHTTP,
-			Formatters:      []httpretty.Formatter{&jsonFormatter{colorize: opts.LogColorize}},
-			MaxResponseBody: 100000,
+		redactor := newRedactor(opts.LogRedactKeys)
+		if opts.LogJSON {
+			transport = newInstrumentRoundTripper(newLogInstrumenter(opts.Log, opts.LogVerboseHTTP, redactor), transport)
+		} else {
+			logger := &httpretty.Logger{
+				Time:           true,
+				TLS:            false,
+				Colors:         opts.LogColorize,
+				RequestHeader:  opts.LogVerboseHTTP,
+				RequestBody:    opts.LogVerboseHTTP,
+				ResponseHeader: opts.LogVerboseHTTP,
+				ResponseBody:   opts.LogVerboseHTTP,
+				Formatters: []httpretty.Formatter{
+					&jsonFormatter{colorize: opts.LogColorize, redactor: redactor},
+					&formFormatter{redactor: redactor},
+				},
+				MaxResponseBody: 100000,
+			}
+			logger.SetOutput(opts.Log)
+			logger.SetBodyFilter(func(h http.Header) (skip bool, err error) {
+				return !inspectableMIMEType(h.Get(contentType)), nil
+			})
+			if opts.LogVerboseHTTP {
+				transport = newRedactedLogRoundTripper(redactor, logger, transport)
+			} else {
+				transport = logger.RoundTripper(transport)
+			}
 		}
-		logger.SetOutput(opts.Log)
-		logger.SetBodyFilter(func(h http.Header) (skip bool, err error) {
-			return !inspectableMIMEType(h.Get(contentType)), nil
-		})
-		transport = logger.RoundTripper(transport)
 	}
 
+	transport = applyMiddleware(transport, opts.Middleware, MiddlewareAfterHeaders)
+
 	if opts.Headers == nil {
 		opts.Headers = map[string]string{}
 	}
@@ -115,6 +145,12 @@ func NewHTTPClient(opts ClientOptions) (*http.Client, error) {
 	}
 	transport = newHeaderRoundTripper(opts.Host, opts.AuthToken, opts.Headers, transport)
 
+	transport = applyMiddleware(transport, opts.Middleware, MiddlewareBeforeHeaders)
+
+	if opts.Instrumenter != nil {
+		transport = newInstrumentRoundTripper(opts.Instrumenter, transport)
+	}
+
 	return &http.Client{Transport: transport, Timeout: opts.Timeout}, nil
 }
 
@@ -199,18 +235,6 @@ func (hrt headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, erro
 	return hrt.rt.RoundTrip(req)
 }
 
-func newUnixDomainSocketRoundTripper(socketPath string) http.RoundTripper {
-	dial := func(network, addr string) (net.Conn, error) {
-		return net.Dial("unix", socketPath)
-	}
-
-	return &http.Transport{
-		Dial:              dial,
-		DialTLS:           dial,
-		DisableKeepAlives: true,
-	}
-}
-
 type sanitizerRoundTripper struct {
 	rt http.RoundTripper
 }
@@ -0,0 +1,381 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/v2/internal/graphql"
+)
+
+const (
+	rateLimitLimit     = "X-RateLimit-Limit"
+	rateLimitRemaining = "X-RateLimit-Remaining"
+	rateLimitReset     = "X-RateLimit-Reset"
+)
+
+var (
+	numericRE = regexp.MustCompile(`^[0-9]+$`)
+	shaRE     = regexp.MustCompile(`^[0-9a-f]{40}$|^[0-9a-f]{64}$`)
+)
+
+// Instrumenter receives events about the API requests made by a client.
+// It is shaped so that it can be implemented on top of an OpenTelemetry
+// tracer and meter without this module depending on OpenTelemetry:
+// RequestStarted can start a span and return a context containing it,
+// and RequestFinished can end that span and record metrics using the
+// values from RequestEvent.Attributes.
+//
+// Implementations must be safe for concurrent use.
+type Instrumenter interface {
+	// RequestStarted is called before a request is sent. The returned context
+	// is used for the remainder of the request, allowing values such as spans
+	// to be propagated to the rest of the transport chain.
+	RequestStarted(ctx context.Context, event *RequestEvent) context.Context
+
+	// RequestFinished is called once the response body has been fully read
+	// or closed, or when the request has failed. The context is the one that
+	// was returned from RequestStarted.
+	RequestFinished(ctx context.Context, event *RequestEvent)
+}
+
+// RequestEvent describes a single API request.
+// Fields describing the response are only populated once the request has finished.
+type RequestEvent struct {
+	// Method is the HTTP method of the request.
+	Method string
+
+	// URL is the full URL of the request.
+	URL *url.URL
+
+	// PathTemplate is the request path with identifiers such as owner, repository,
+	// and numbers replaced by placeholders, suitable for use as a low cardinality
+	// metric dimension. For example, "/repos/cli/cli/issues/1" is templated as
+	// "/repos/{owner}/{repo}/issues/{id}".
+	PathTemplate string
+
+	// GraphQLOperation is the name of the GraphQL operation for GraphQL requests.
+	GraphQLOperation string
+
+	// RequestHeader is the header of the request. Headers added further
+	// along the transport chain, such as Authorization, may be present.
+	RequestHeader http.Header
+
+	// StartTime is when the request was started.
+	StartTime time.Time
+
+	// Duration is the time taken from the request starting until the response
+	// body was read or closed.
+	Duration time.Duration
+
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// ResponseHeader is the header of the response.
+	ResponseHeader http.Header
+
+	// RequestBytes is the number of bytes of the request body that were sent.
+	RequestBytes int64
+
+	// ResponseBytes is the number of bytes of the response body that were read.
+	ResponseBytes int64
+
+	// CacheHit reports whether the response was served from the cache.
+	CacheHit bool
+
+	// Retries is the number of times the request was resent to the server
+	// after the first attempt, for example by retrying middleware.
+	Retries int
+
+	// RateLimitLimit is the maximum number of requests permitted in the current
+	// rate limit window, or -1 if it was not reported.
+	RateLimitLimit int
+
+	// RateLimitRemaining is the number of requests remaining in the current
+	// rate limit window, or -1 if it was not reported.
+	RateLimitRemaining int
+
+	// RateLimitReset is when the current rate limit window resets, or the zero
+	// time if it was not reported.
+	RateLimitReset time.Time
+
+	// Err is the error that caused the request to fail, if any.
+	Err error
+}
+
+// Attributes returns the event as key-value pairs named after the
+// OpenTelemetry semantic conventions for HTTP clients where applicable.
+func (e *RequestEvent) Attributes() map[string]interface{} {
+	attrs := map[string]interface{}{
+		"http.request.method":       e.Method,
+		"url.template":              e.PathTemplate,
+		"http.request.body.size":    e.RequestBytes,
+		"http.response.body.size":   e.ResponseBytes,
+		"http.request.resend_count": e.Retries,
+		"gh.cache.hit":              e.CacheHit,
+	}
+	if e.URL != nil {
+		attrs["url.full"] = e.URL.String()
+		attrs["server.address"] = e.URL.Hostname()
+	}
+	if e.GraphQLOperation != "" {
+		attrs["graphql.operation.name"] = e.GraphQLOperation
+	}
+	if e.StatusCode != 0 {
+		attrs["http.response.status_code"] = e.StatusCode
+	}
+	if e.RateLimitRemaining >= 0 {
+		attrs["gh.ratelimit.remaining"] = e.RateLimitRemaining
+	}
+	if e.RateLimitLimit >= 0 {
+		attrs["gh.ratelimit.limit"] = e.RateLimitLimit
+	}
+	if e.Err != nil {
+		attrs["error.type"] = e.Err.Error()
+	}
+	return attrs
+}
+
+// requestState is shared through the request context between the
+// stages of the transport chain that contribute to a RequestEvent.
+type requestState struct {
+	mu       sync.Mutex
+	cacheHit bool
+	attempts int
+}
+
+type requestStateKey struct{}
+
+func withRequestState(ctx context.Context) (context.Context, *requestState) {
+	if state := requestStateFromContext(ctx); state != nil {
+		return ctx, state
+	}
+	state := &requestState{}
+	return context.WithValue(ctx, requestStateKey{}, state), state
+}
+
+func requestStateFromContext(ctx context.Context) *requestState {
+	state, _ := ctx.Value(requestStateKey{}).(*requestState)
+	return state
+}
+
+func markCacheHit(req *http.Request) {
+	if state := requestStateFromContext(req.Context()); state != nil {
+		state.mu.Lock()
+		state.cacheHit = true
+		state.mu.Unlock()
+	}
+}
+
+// attemptRoundTripper counts the number of requests sent to the base transport.
+type attemptRoundTripper struct {
+	rt http.RoundTripper
+}
+
+func (art attemptRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if state := requestStateFromContext(req.Context()); state != nil {
+		state.mu.Lock()
+		state.attempts++
+		state.mu.Unlock()
+	}
+	return art.rt.RoundTrip(req)
+}
+
+type instrumentRoundTripper struct {
+	instrumenter Instrumenter
+	rt           http.RoundTripper
+}
+
+func newInstrumentRoundTripper(instrumenter Instrumenter, rt http.RoundTripper) http.RoundTripper {
+	return instrumentRoundTripper{instrumenter: instrumenter, rt: rt}
+}
+
+func (irt instrumentRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	event := &RequestEvent{
+		Method:             req.Method,
+		URL:                req.URL,
+		PathTemplate:       templatePath(req.URL.Path),
+		GraphQLOperation:   graphQLOperationName(req),
+		RequestHeader:      req.Header,
+		StartTime:          time.Now(),
+		RateLimitLimit:     -1,
+		RateLimitRemaining: -1,
+	}
+
+	ctx, state := withRequestState(req.Context())
+	ctx = irt.instrumenter.RequestStarted(ctx, event)
+	req = req.WithContext(ctx)
+
+	var reqBody *countingReadCloser
+	if req.Body != nil && req.Body != http.NoBody {
+		reqBody = &countingReadCloser{ReadCloser: req.Body}
+		req.Body = reqBody
+	}
+
+	finish := func(n int64) {
+		event.Duration = time.Since(event.StartTime)
+		event.ResponseBytes = n
+		if req.ContentLength > 0 {
+			event.RequestBytes = req.ContentLength
+		} else if reqBody != nil {
+			event.RequestBytes = reqBody.count()
+		}
+		state.mu.Lock()
+		event.CacheHit = state.cacheHit
+		if state.attempts > 1 {
+			event.Retries = state.attempts - 1
+		}
+		state.mu.Unlock()
+		irt.instrumenter.RequestFinished(ctx, event)
+	}
+
+	resp, err := irt.rt.RoundTrip(req)
+	if err != nil {
+		event.Err = err
+		finish(0)
+		return resp, err
+	}
+
+	event.StatusCode = resp.StatusCode
+	event.ResponseHeader = resp.Header
+	readRateLimit(event, resp.Header)
+
+	if resp.Body == nil {
+		finish(0)
+		return resp, nil
+	}
+	resp.Body = &instrumentedBody{
+		countingReadCloser: countingReadCloser{ReadCloser: resp.Body},
+		finish:             finish,
+	}
+	return resp, nil
+}
+
+func readRateLimit(event *RequestEvent, h http.Header) {
+	if v, err := strconv.Atoi(h.Get(rateLimitLimit)); err == nil {
+		event.RateLimitLimit = v
+	}
+	if v, err := strconv.Atoi(h.Get(rateLimitRemaining)); err == nil {
+		event.RateLimitRemaining = v
+	}
+	if v, err := strconv.ParseInt(h.Get(rateLimitReset), 10, 64); err == nil {
+		event.RateLimitReset = time.Unix(v, 0)
+	}
+}
+
+type countingReadCloser struct {
+	io.ReadCloser
+	mu sync.Mutex
+	n  int64
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.mu.Lock()
+	c.n += int64(n)
+	c.mu.Unlock()
+	return n, err
+}
+
+func (c *countingReadCloser) count() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}
+
+// instrumentedBody reports a finished request once the body has been
+// read to the end or closed, whichever happens first.
+type instrumentedBody struct {
+	countingReadCloser
+	once   sync.Once
+	finish func(int64)
+}
+
+func (b *instrumentedBody) Read(p []byte) (int, error) {
+	n, err := b.countingReadCloser.Read(p)
+	if err == io.EOF {
+		b.once.Do(func() { b.finish(b.count()) })
+	}
+	return n, err
+}
+
+func (b *instrumentedBody) Close() error {
+	err := b.countingReadCloser.Close()
+	b.once.Do(func() { b.finish(b.count()) })
+	return err
+}
+
+// templatePath replaces the variable segments of a GitHub API path with placeholders.
+// This is a best-effort heuristic intended to keep the cardinality of metrics low.
+func templatePath(p string) string {
+	prefix := ""
+	if strings.HasPrefix(p, "/api/v3/") {
+		prefix = "/api/v3"
+		p = strings.TrimPrefix(p, prefix)
+	}
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+	if len(segments) == 1 && segments[0] == "" {
+		return prefix + "/"
+	}
+	for i := 0; i < len(segments); i++ {
+		var parent string
+		if i > 0 {
+			parent = segments[i-1]
+		}
+		switch {
+		case i == 1 && segments[0] == "repos":
+			segments[i] = "{owner}"
+		case i == 2 && segments[0] == "repos":
+			segments[i] = "{repo}"
+		case i == 1 && segments[0] == "users":
+			segments[i] = "{username}"
+		case i == 1 && segments[0] == "orgs":
+			segments[i] = "{org}"
+		case i == 1 && segments[0] == "enterprises":
+			segments[i] = "{enterprise}"
+		case parent == "contents" || parent == "refs" || parent == "ref":
+			// The remainder of the path is a file path or git reference.
+			segments = append(segments[:i], "{path}")
+		case parent == "branches":
+			segments[i] = "{branch}"
+		case parent == "labels":
+			segments[i] = "{name}"
+		case parent == "commits" || parent == "compare":
+			segments[i] = "{ref}"
+		case parent == "tags" && i > 1 && segments[i-2] == "releases":
+			segments[i] = "{tag}"
+		case numericRE.MatchString(segments[i]):
+			segments[i] = "{id}"
+		case shaRE.MatchString(segments[i]):
+			segments[i] = "{sha}"
+		}
+	}
+	return prefix + "/" + strings.Join(segments, "/")
+}
+
+// graphQLOperationName returns the operation name of a GraphQL request,
+// falling back to the name declared in the query when none was specified.
+func graphQLOperationName(req *http.Request) string {
+	if req.Method != http.MethodPost || !strings.HasSuffix(req.URL.Path, "/graphql") || req.Body == nil {
+		return ""
+	}
+	var bodyCopy io.ReadCloser
+	req.Body, bodyCopy = copyStream(req.Body)
+	defer bodyCopy.Close()
+	data, err := io.ReadAll(bodyCopy)
+	if err != nil {
+		return ""
+	}
+	body, err := graphql.ParseRequest(data)
+	if err != nil {
+		return ""
+	}
+	_, name, _ := body.Operation()
+	return name
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingInstrumenter struct {
+	mu       sync.Mutex
+	started  []*RequestEvent
+	finished []RequestEvent
+}
+
+type spanKey struct{}
+
+func (ri *recordingInstrumenter) RequestStarted(ctx context.Context, e *RequestEvent) context.Context {
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+	ri.started = append(ri.started, e)
+	return context.WithValue(ctx, spanKey{}, len(ri.started))
+}
+
+func (ri *recordingInstrumenter) RequestFinished(ctx context.Context, e *RequestEvent) {
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+	ri.finished = append(ri.finished, *e)
+}
+
+func TestInstrumenter(t *testing.T) {
+	var spans []interface{}
+	fakeHTTP := tripper{
+		roundTrip: func(req *http.Request) (*http.Response, error) {
+			spans = append(spans, req.Context().Value(spanKey{}))
+			header := http.Header{}
+			header.Set(rateLimitLimit, "5000")
+			header.Set(rateLimitRemaining, "4999")
+			header.Set(rateLimitReset, "1700000000")
+			return &http.Response{
+				StatusCode: 200,
+				Header:     header,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"message": "success"}`)),
+			}, nil
+		},
+	}
+	retry := Middleware{
+		Position: MiddlewareAfterCache,
+		Wrap: func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				if req.Method == http.MethodPost {
+					if _, err := next.RoundTrip(req); err != nil {
+						return nil, err
+					}
+				}
+				return next.RoundTrip(req)
+			})
+		},
+	}
+	instrumenter := &recordingInstrumenter{}
+
+	client, err := NewHTTPClient(ClientOptions{
+		Host:         "github.com",
+		AuthToken:    "token",
+		Transport:    fakeHTTP,
+		EnableCache:  true,
+		CacheDir:     filepath.Join(t.TempDir(), "gh-cli-cache"),
+		LogIgnoreEnv: true,
+		Instrumenter: instrumenter,
+		Middleware:   []Middleware{retry},
+	})
+	assert.NoError(t, err)
+
+	do := func(method, url string, body io.Reader) {
+		req, err := http.NewRequest(method, url, body)
+		assert.NoError(t, err)
+		res, err := client.Do(req)
+		assert.NoError(t, err)
+		_, err = io.ReadAll(res.Body)
+		assert.NoError(t, err)
+		res.Body.Close()
+	}
+
+	do("GET", "https://api.github.com/repos/cli/cli/issues/123", nil)
+	do("GET", "https://api.github.com/repos/cli/cli/issues/123", nil)
+	do("POST", "https://api.github.com/repos/cli/cli/issues", bytes.NewBufferString(`{"title": "test"}`))
+
+	assert.Equal(t, []interface{}{1, 3, 3}, spans)
+	assert.Len(t, instrumenter.finished, 3)
+
+	first := instrumenter.finished[0]
+	assert.Equal(t, "GET", first.Method)
+	assert.Equal(t, "/repos/{owner}/{repo}/issues/{id}", first.PathTemplate)
+	assert.Equal(t, 200, first.StatusCode)
+	assert.Equal(t, int64(22), first.ResponseBytes)
+	assert.False(t, first.CacheHit)
+	assert.Equal(t, 0, first.Retries)
+	assert.Equal(t, 5000, first.RateLimitLimit)
+	assert.Equal(t, 4999, first.RateLimitRemaining)
+	assert.Equal(t, time.Unix(1700000000, 0), first.RateLimitReset)
+	assert.NoError(t, first.Err)
+
+	second := instrumenter.finished[1]
+	assert.True(t, second.CacheHit)
+	assert.Equal(t, int64(22), second.ResponseBytes)
+
+	third := instrumenter.finished[2]
+	assert.Equal(t, "POST", third.Method)
+	assert.Equal(t, "/repos/{owner}/{repo}/issues", third.PathTemplate)
+	assert.Equal(t, int64(17), third.RequestBytes)
+	assert.False(t, third.CacheHit)
+	assert.Equal(t, 1, third.Retries)
+}
+
+func TestInstrumenterError(t *testing.T) {
+	fakeHTTP := tripper{
+		roundTrip: func(req *http.Request) (*http.Response, error) {
+			return nil, io.ErrUnexpectedEOF
+		},
+	}
+	instrumenter := &recordingInstrumenter{}
+
+	client, err := NewHTTPClient(ClientOptions{
+		Host:         "github.com",
+		AuthToken:    "token",
+		Transport:    fakeHTTP,
+		LogIgnoreEnv: true,
+		Instrumenter: instrumenter,
+	})
+	assert.NoError(t, err)
+
+	_, err = client.Get("https://api.github.com/user")
+	assert.Error(t, err)
+	assert.Len(t, instrumenter.finished, 1)
+	assert.ErrorIs(t, instrumenter.finished[0].Err, io.ErrUnexpectedEOF)
+	assert.Equal(t, -1, instrumenter.finished[0].RateLimitRemaining)
+}
+
+func TestTemplatePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/", want: "/"},
+		{path: "/graphql", want: "/graphql"},
+		{path: "/api/graphql", want: "/api/graphql"},
+		{path: "/user", want: "/user"},
+		{path: "/users/monalisa/repos", want: "/users/{username}/repos"},
+		{path: "/orgs/github/teams", want: "/orgs/{org}/teams"},
+		{path: "/repos/cli/cli", want: "/repos/{owner}/{repo}"},
+		{path: "/repos/cli/cli/pulls/42/comments", want: "/repos/{owner}/{repo}/pulls/{id}/comments"},
+		{path: "/repos/cli/cli/contents/pkg/api/cache.go", want: "/repos/{owner}/{repo}/contents/{path}"},
+		{path: "/repos/cli/cli/git/refs/heads/trunk", want: "/repos/{owner}/{repo}/git/refs/{path}"},
+		{path: "/repos/cli/cli/branches/trunk/protection", want: "/repos/{owner}/{repo}/branches/{branch}/protection"},
+		{path: "/repos/cli/cli/labels/bug", want: "/repos/{owner}/{repo}/labels/{name}"},
+		{path: "/repos/cli/cli/commits/trunk", want: "/repos/{owner}/{repo}/commits/{ref}"},
+		{path: "/repos/cli/cli/releases/tags/v2.0.0", want: "/repos/{owner}/{repo}/releases/tags/{tag}"},
+		{path: "/repos/cli/cli/git/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e", want: "/repos/{owner}/{repo}/git/commits/{ref}"},
+		{path: "/repos/cli/cli/git/trees/6dcb09b5b57875f334f61aebed695e2e4193db5e", want: "/repos/{owner}/{repo}/git/trees/{sha}"},
+		{path: "/api/v3/repos/cli/cli/issues", want: "/api/v3/repos/{owner}/{repo}/issues"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, templatePath(tt.path))
+		})
+	}
+}
@@ -2,29 +2,24 @@ package api
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
 
+	"github.com/cli/go-gh/v2/internal/graphql"
 	"github.com/cli/go-gh/v2/pkg/jsonpretty"
 )
 
-type graphqlBody struct {
-	Query         string          `json:"query"`
-	OperationName string          `json:"operationName"`
-	Variables     json.RawMessage `json:"variables"`
-}
-
 // jsonFormatter is a httpretty.Formatter that prettifies JSON payloads and GraphQL queries.
+// Sensitive fields are masked when a redactor is set.
 type jsonFormatter struct {
 	colorize bool
+	redactor *redactor
 }
 
 func (f *jsonFormatter) Format(w io.Writer, src []byte) error {
-	var graphqlQuery graphqlBody
 	// TODO: find more precise way to detect a GraphQL query from the JSON payload alone
-	if err := json.Unmarshal(src, &graphqlQuery); err == nil && graphqlQuery.Query != "" && len(graphqlQuery.Variables) > 0 {
+	if graphqlQuery, err := graphql.ParseRequest(src); err == nil && len(graphqlQuery.Variables) > 0 {
 		colorHighlight := "\x1b[35;1m"
 		colorReset := "\x1b[m"
 		if !f.colorize {
@@ -34,11 +29,27 @@ func (f *jsonFormatter) Format(w io.Writer, src []byte) error {
 		if _, err := fmt.Fprintf(w, "%sGraphQL query:%s\n%s\n", colorHighlight, colorReset, strings.ReplaceAll(strings.TrimSpace(graphqlQuery.Query), "\t", "  ")); err != nil {
 			return err
 		}
-		if _, err := fmt.Fprintf(w, "%sGraphQL variables:%s %s\n", colorHighlight, colorReset, string(graphqlQuery.Variables)); err != nil {
+		variables := graphqlQuery.Variables
+		if f.redactor != nil {
+			v, err := f.redactor.json(variables)
+			if err != nil {
+				v = []byte(unredactable)
+			}
+			variables = v
+		}
+		if _, err := fmt.Fprintf(w, "%sGraphQL variables:%s %s\n", colorHighlight, colorReset, string(variables)); err != nil {
 			return err
 		}
 		return nil
 	}
+	if f.redactor != nil {
+		redacted, err := f.redactor.json(src)
+		if err != nil {
+			_, err = io.WriteString(w, unredactable)
+			return err
+		}
+		src = redacted
+	}
 	return jsonpretty.Format(w, bytes.NewReader(src), "  ", f.colorize)
 }
 
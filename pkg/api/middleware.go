@@ -0,0 +1,66 @@
+package api
+
+import (
+	"net/http"
+)
+
+// MiddlewarePosition specifies where in the API request transport chain
+// a Middleware is inserted. Requests flow through the chain in the order
+// the positions are declared, and responses flow back in reverse order.
+type MiddlewarePosition int
+
+const (
+	// MiddlewareBeforeHeaders inserts middleware at the start of the chain,
+	// before default headers and the authorization token are added to the request.
+	MiddlewareBeforeHeaders MiddlewarePosition = iota
+
+	// MiddlewareAfterHeaders inserts middleware after default headers and the
+	// authorization token have been added to the request, but before the request
+	// is logged. This is the position to use for request signing.
+	MiddlewareAfterHeaders
+
+	// MiddlewareBeforeCache inserts middleware after the request has been logged
+	// and before the cache is consulted. Middleware at this position sees every
+	// request, including those that are served from the cache.
+	MiddlewareBeforeCache
+
+	// MiddlewareAfterCache inserts middleware after the cache. Middleware at this
+	// position only sees requests that were not served from the cache.
+	MiddlewareAfterCache
+
+	// MiddlewareTransport inserts middleware directly around the base transport.
+	// Middleware at this position sees responses before they have been sanitized.
+	MiddlewareTransport
+)
+
+// Middleware wraps the API request transport chain at a given position,
+// allowing consumers to add behavior such as metrics, tracing, request
+// signing, or fault injection without replacing the Transport.
+type Middleware struct {
+	// Position is where in the transport chain the middleware is inserted.
+	Position MiddlewarePosition
+
+	// Wrap returns a http.RoundTripper that wraps the next http.RoundTripper in the chain.
+	Wrap func(next http.RoundTripper) http.RoundTripper
+}
+
+// RoundTripperFunc is an adapter to allow the use of ordinary functions as a http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// applyMiddleware wraps rt with each middleware at the given position. Middleware
+// are applied so that the first one specified is the first one to see a request.
+func applyMiddleware(rt http.RoundTripper, middleware []Middleware, position MiddlewarePosition) http.RoundTripper {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		m := middleware[i]
+		if m.Position != position || m.Wrap == nil {
+			continue
+		}
+		rt = m.Wrap(rt)
+	}
+	return rt
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	calls := []string{}
+	middleware := func(name string, position MiddlewarePosition) Middleware {
+		return Middleware{
+			Position: position,
+			Wrap: func(next http.RoundTripper) http.RoundTripper {
+				return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					auth := "without auth"
+					if req.Header.Get(authorization) != "" {
+						auth = "with auth"
+					}
+					calls = append(calls, name+" "+auth)
+					return next.RoundTrip(req)
+				})
+			},
+		}
+	}
+
+	fakeHTTP := tripper{
+		roundTrip: func(req *http.Request) (*http.Response, error) {
+			calls = append(calls, "transport")
+			return &http.Response{
+				StatusCode: 200,
+				Header:     http.Header{},
+				Body:       io.NopCloser(bytes.NewBufferString("{}")),
+			}, nil
+		},
+	}
+
+	client, err := NewHTTPClient(ClientOptions{
+		Host:         "github.com",
+		AuthToken:    "token",
+		Transport:    fakeHTTP,
+		EnableCache:  true,
+		CacheDir:     filepath.Join(t.TempDir(), "gh-cli-cache"),
+		LogIgnoreEnv: true,
+		Middleware: []Middleware{
+			middleware("transport", MiddlewareTransport),
+			middleware("after cache", MiddlewareAfterCache),
+			middleware("before cache", MiddlewareBeforeCache),
+			middleware("after headers", MiddlewareAfterHeaders),
+			middleware("before headers 1", MiddlewareBeforeHeaders),
+			middleware("before headers 2", MiddlewareBeforeHeaders),
+		},
+	})
+	assert.NoError(t, err)
+
+	res, err := client.Get("https://api.github.com/some/path")
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, []string{
+		"before headers 1 without auth",
+		"before headers 2 without auth",
+		"after headers with auth",
+		"before cache with auth",
+		"after cache with auth",
+		"transport with auth",
+		"transport",
+	}, calls)
+
+	calls = []string{}
+	res, err = client.Get("https://api.github.com/some/path")
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, []string{
+		"before headers 1 without auth",
+		"before headers 2 without auth",
+		"after headers with auth",
+		"before cache with auth",
+	}, calls)
+}
@@ -0,0 +1,269 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/henvic/httpretty"
+)
+
+const redacted = "[REDACTED]"
+
+// unredactable replaces a logged body that could not be parsed for redaction.
+const unredactable = "[body not logged: could not redact]"
+
+var sensitiveHeaders = []string{
+	authorization,
+	"Cookie",
+	"Proxy-Authorization",
+	"Set-Cookie",
+}
+
+var sensitiveFields = []string{
+	"access_token",
+	"client_secret",
+	"device_code",
+	"encrypted_value",
+	"oauth_token",
+	"password",
+	"private_key",
+	"refresh_token",
+	"secret",
+	"token",
+}
+
+// redactor masks credentials in headers and request and response bodies
+// before they are written to logs.
+type redactor struct {
+	headers map[string]struct{}
+	fields  map[string]struct{}
+}
+
+// newRedactor returns a redactor for the default sensitive headers and body
+// fields, plus any additional keys which are matched against both.
+func newRedactor(keys []string) *redactor {
+	r := &redactor{
+		headers: map[string]struct{}{},
+		fields:  map[string]struct{}{},
+	}
+	for _, k := range sensitiveHeaders {
+		r.headers[http.CanonicalHeaderKey(k)] = struct{}{}
+	}
+	for _, k := range sensitiveFields {
+		r.fields[strings.ToLower(k)] = struct{}{}
+	}
+	for _, k := range keys {
+		r.headers[http.CanonicalHeaderKey(k)] = struct{}{}
+		r.fields[strings.ToLower(k)] = struct{}{}
+	}
+	return r
+}
+
+func (r *redactor) isSensitiveField(key string) bool {
+	_, ok := r.fields[strings.ToLower(key)]
+	return ok
+}
+
+// header returns a copy of h with the values of sensitive headers masked.
+// The scheme of authorization headers is preserved.
+func (r *redactor) header(h http.Header) http.Header {
+	h = h.Clone()
+	for k, values := range h {
+		if _, ok := r.headers[http.CanonicalHeaderKey(k)]; !ok {
+			continue
+		}
+		for i, v := range values {
+			if scheme, _, ok := strings.Cut(v, " "); ok && strings.HasSuffix(http.CanonicalHeaderKey(k), authorization) {
+				values[i] = scheme + " " + redacted
+			} else {
+				values[i] = redacted
+			}
+		}
+	}
+	return h
+}
+
+// form returns a copy of the URL encoded form data with sensitive fields masked.
+func (r *redactor) form(src []byte) ([]byte, error) {
+	values, err := url.ParseQuery(string(src))
+	if err != nil {
+		return nil, err
+	}
+	for k := range values {
+		if r.isSensitiveField(k) {
+			for i := range values[k] {
+				values[k][i] = redacted
+			}
+		}
+	}
+	return []byte(values.Encode()), nil
+}
+
+// json returns a compact copy of the JSON document with the values of sensitive
+// fields masked, at any depth. The order of object keys is preserved.
+func (r *redactor) json(src []byte) ([]byte, error) {
+	type container struct {
+		object   bool
+		count    int
+		afterKey bool
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(src))
+	dec.UseNumber()
+	var out bytes.Buffer
+	var stack []*container
+
+	// beginValue writes the separator that precedes a value.
+	beginValue := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := stack[len(stack)-1]
+		if top.object {
+			top.afterKey = false
+			top.count++
+			return
+		}
+		if top.count > 0 {
+			out.WriteByte(',')
+		}
+		top.count++
+	}
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if key, ok := tok.(string); ok && top.object && !top.afterKey {
+				if top.count > 0 {
+					out.WriteByte(',')
+				}
+				b, _ := json.Marshal(key)
+				out.Write(b)
+				out.WriteByte(':')
+				top.afterKey = true
+				if r.isSensitiveField(key) {
+					beginValue()
+					out.WriteString(`"` + redacted + `"`)
+					if err := skipJSONValue(dec); err != nil {
+						return nil, err
+					}
+				}
+				continue
+			}
+		}
+
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			beginValue()
+			out.WriteString(tok.(json.Delim).String())
+			stack = append(stack, &container{object: tok == json.Delim('{')})
+		case json.Delim('}'), json.Delim(']'):
+			out.WriteString(tok.(json.Delim).String())
+			stack = stack[:len(stack)-1]
+		default:
+			beginValue()
+			b, err := json.Marshal(tok)
+			if err != nil {
+				return nil, err
+			}
+			out.Write(b)
+		}
+	}
+	if len(stack) > 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return out.Bytes(), nil
+}
+
+// skipJSONValue consumes the next value from dec, including any nested values.
+func skipJSONValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// formFormatter is a httpretty.Formatter that masks sensitive fields in URL encoded form data.
+type formFormatter struct {
+	redactor *redactor
+}
+
+func (f *formFormatter) Format(w io.Writer, src []byte) error {
+	b, err := f.redactor.form(src)
+	if err != nil {
+		b = []byte(unredactable)
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func (f *formFormatter) Match(t string) bool {
+	return t == "application/x-www-form-urlencoded"
+}
+
+type headerSwapKey struct{}
+
+// headerSwap holds the original headers of a request and its response
+// while redacted copies of them are being logged.
+type headerSwap struct {
+	request  http.Header
+	response http.Header
+}
+
+// newRedactedLogRoundTripper wraps a httpretty.Logger so that it logs redacted
+// copies of request and response headers, while the original headers are the
+// ones that are sent to the server and returned to the caller.
+func newRedactedLogRoundTripper(r *redactor, logger *httpretty.Logger, rt http.RoundTripper) http.RoundTripper {
+	restore := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		swap, _ := req.Context().Value(headerSwapKey{}).(*headerSwap)
+		if swap == nil {
+			return rt.RoundTrip(req)
+		}
+		req.Header = swap.request
+		resp, err := rt.RoundTrip(req)
+		if resp != nil {
+			swap.response = resp.Header
+			resp.Header = r.header(resp.Header)
+		}
+		return resp, err
+	})
+	logged := logger.RoundTripper(restore)
+
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		swap := &headerSwap{request: req.Header}
+		clone := req.Clone(context.WithValue(req.Context(), headerSwapKey{}, swap))
+		clone.Header = r.header(req.Header)
+		resp, err := logged.RoundTrip(clone)
+		if resp != nil && swap.response != nil {
+			resp.Header = swap.response
+		}
+		return resp, err
+	})
+}
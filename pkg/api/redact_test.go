@@ -0,0 +1,190 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactorHeader(t *testing.T) {
+	h := http.Header{}
+	h.Set("Authorization", "token secret")
+	h.Set("Proxy-Authorization", "secret")
+	h.Add("Cookie", "a=secret")
+	h.Set("X-Api-Key", "secret")
+	h.Set("Accept", "application/json")
+
+	r := newRedactor([]string{"x-api-key"})
+	redactedHeader := r.header(h)
+	assert.Equal(t, "token [REDACTED]", redactedHeader.Get("Authorization"))
+	assert.Equal(t, "[REDACTED]", redactedHeader.Get("Proxy-Authorization"))
+	assert.Equal(t, "[REDACTED]", redactedHeader.Get("Cookie"))
+	assert.Equal(t, "[REDACTED]", redactedHeader.Get("X-Api-Key"))
+	assert.Equal(t, "application/json", redactedHeader.Get("Accept"))
+	assert.Equal(t, "token secret", h.Get("Authorization"))
+}
+
+func TestRedactorJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    []string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "no sensitive fields",
+			input: `{"b": 1, "a": [true, null, "x"], "c": {"d": 1.5}}`,
+			want:  `{"b":1,"a":[true,null,"x"],"c":{"d":1.5}}`,
+		},
+		{
+			name:  "sensitive fields at any depth",
+			input: `{"name": "x", "client_secret": "abc", "nested": [{"Token": "abc", "id": 1}], "password": {"a": [1, 2]}}`,
+			want:  `{"name":"x","client_secret":"[REDACTED]","nested":[{"Token":"[REDACTED]","id":1}],"password":"[REDACTED]"}`,
+		},
+		{
+			name:  "additional keys",
+			keys:  []string{"ssn"},
+			input: `[{"ssn": 123}, {"other": 1}]`,
+			want:  `[{"ssn":"[REDACTED]"},{"other":1}]`,
+		},
+		{
+			name:  "scalar",
+			input: `"token"`,
+			want:  `"token"`,
+		},
+		{
+			name:    "invalid json",
+			input:   `{"token": `,
+			wantErr: true,
+		},
+		{
+			name:    "truncated json",
+			input:   `{"login": "monalisa", "token": "secret"`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := newRedactor(tt.keys).json([]byte(tt.input))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(out))
+		})
+	}
+}
+
+func TestRedactorForm(t *testing.T) {
+	r := newRedactor(nil)
+	out, err := r.form([]byte("client_id=abc&client_secret=def&code=123"))
+	assert.NoError(t, err)
+	assert.Equal(t, "client_id=abc&client_secret=%5BREDACTED%5D&code=123", string(out))
+}
+
+func TestVerboseLogRedaction(t *testing.T) {
+	var sentHeader http.Header
+	fakeHTTP := tripper{
+		roundTrip: func(req *http.Request) (*http.Response, error) {
+			sentHeader = req.Header.Clone()
+			header := http.Header{}
+			header.Set(contentType, "application/json")
+			header.Set("X-Session", "response-secret")
+			return &http.Response{
+				StatusCode:    200,
+				Header:        header,
+				ContentLength: -1,
+				Body:          io.NopCloser(bytes.NewBufferString(`{"token": "response-secret", "login": "monalisa"}`)),
+			}, nil
+		},
+	}
+
+	log := &bytes.Buffer{}
+	client, err := NewHTTPClient(ClientOptions{
+		Host:           "github.com",
+		AuthToken:      "auth-secret",
+		Headers:        map[string]string{"X-Signature": "signature-secret"},
+		Transport:      fakeHTTP,
+		Log:            log,
+		LogVerboseHTTP: true,
+		LogRedactKeys:  []string{"X-Signature", "X-Session", "custom_field"},
+	})
+	assert.NoError(t, err)
+
+	body := `{"query": "mutation { createSecret }", "variables": {"input": {"encrypted_value": "variable-secret", "custom_field": "custom-secret", "name": "NAME"}}}`
+	res, err := client.Post("https://api.github.com/graphql", "application/json", bytes.NewBufferString(body))
+	assert.NoError(t, err)
+	resBody, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+	res.Body.Close()
+
+	assert.Equal(t, "token auth-secret", sentHeader.Get(authorization))
+	assert.Equal(t, "signature-secret", sentHeader.Get("X-Signature"))
+	assert.Equal(t, "response-secret", res.Header.Get("X-Session"))
+	assert.Equal(t, `{"token": "response-secret", "login": "monalisa"}`, string(resBody))
+
+	output := log.String()
+	for _, secret := range []string{"auth-secret", "signature-secret", "response-secret", "variable-secret", "custom-secret"} {
+		assert.NotContains(t, output, secret)
+	}
+	assert.Contains(t, output, "X-Signature: [REDACTED]")
+	assert.Contains(t, output, `"name":"NAME"`)
+	assert.Contains(t, output, "monalisa")
+}
+
+func TestVerboseLogUnredactableBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{
+			name:        "malformed json",
+			contentType: "application/json",
+			body:        `{"client_secret": "body-secret", "code": `,
+		},
+		{
+			name:        "malformed form",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "client_secret=body-secret&code=%zz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeHTTP := tripper{
+				roundTrip: func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: 204,
+						Header:     http.Header{},
+						Body:       io.NopCloser(&bytes.Buffer{}),
+					}, nil
+				},
+			}
+
+			log := &bytes.Buffer{}
+			client, err := NewHTTPClient(ClientOptions{
+				Host:           "github.com",
+				AuthToken:      "auth-secret",
+				Transport:      fakeHTTP,
+				Log:            log,
+				LogVerboseHTTP: true,
+			})
+			assert.NoError(t, err)
+
+			res, err := client.Post("https://api.github.com/graphql", tt.contentType, bytes.NewBufferString(tt.body))
+			assert.NoError(t, err)
+			res.Body.Close()
+
+			output := log.String()
+			assert.NotContains(t, output, "body-secret")
+			assert.Contains(t, output, unredactable)
+		})
+	}
+}
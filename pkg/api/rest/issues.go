@@ -0,0 +1,135 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/internal/restapi"
+	"github.com/cli/go-gh/v2/pkg/repository"
+)
+
+// Issue is a GitHub issue. Pull requests are issues too, and are
+// returned by IssuesService.List alongside other issues.
+type Issue struct {
+	ID          int64      `json:"id"`
+	Number      int        `json:"number"`
+	Title       string     `json:"title"`
+	Body        string     `json:"body"`
+	State       string     `json:"state"`
+	User        User       `json:"user"`
+	Labels      []Label    `json:"labels"`
+	Assignees   []User     `json:"assignees"`
+	Comments    int        `json:"comments"`
+	URL         string     `json:"html_url"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ClosedAt    *time.Time `json:"closed_at"`
+	PullRequest *struct {
+		URL string `json:"url"`
+	} `json:"pull_request,omitempty"`
+}
+
+// IsPullRequest reports whether the issue is a pull request.
+func (i *Issue) IsPullRequest() bool {
+	return i.PullRequest != nil
+}
+
+// Comment is a comment on an issue or pull request.
+type Comment struct {
+	ID        int64     `json:"id"`
+	Body      string    `json:"body"`
+	User      User      `json:"user"`
+	URL       string    `json:"html_url"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IssueListOptions holds options for listing issues.
+type IssueListOptions struct {
+	// State is one of "open", "closed", or "all".
+	// Default is "open".
+	State string
+
+	// Labels limits the issues to those with all of the labels.
+	Labels []string
+
+	// Assignee limits the issues to those assigned to a user.
+	Assignee string
+
+	ListOptions
+}
+
+// IssueRequest holds the fields of an issue to create or update.
+// Empty fields are left unchanged when updating an issue.
+type IssueRequest struct {
+	Title     string   `json:"title,omitempty"`
+	Body      string   `json:"body,omitempty"`
+	State     string   `json:"state,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+}
+
+// IssuesService provides methods for issues and their comments.
+type IssuesService service
+
+// List returns the issues of a repository.
+func (s *IssuesService) List(ctx context.Context, repo repository.Repository, opts IssueListOptions) ([]Issue, error) {
+	query := url.Values{}
+	if opts.State != "" {
+		query.Set("state", opts.State)
+	}
+	if len(opts.Labels) > 0 {
+		query.Set("labels", strings.Join(opts.Labels, ","))
+	}
+	if opts.Assignee != "" {
+		query.Set("assignee", opts.Assignee)
+	}
+	return list[Issue](ctx, (*service)(s), restapi.RepoPath(repo, "issues"), query, opts.Limit)
+}
+
+// Get returns an issue.
+func (s *IssuesService) Get(ctx context.Context, repo repository.Repository, number int) (*Issue, error) {
+	var issue Issue
+	if err := (*service)(s).do(ctx, http.MethodGet, restapi.RepoPath(repo, "issues/%d", number), nil, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// Create creates an issue.
+func (s *IssuesService) Create(ctx context.Context, repo repository.Repository, req IssueRequest) (*Issue, error) {
+	var issue Issue
+	if err := (*service)(s).do(ctx, http.MethodPost, restapi.RepoPath(repo, "issues"), req, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// Update updates an issue.
+func (s *IssuesService) Update(ctx context.Context, repo repository.Repository, number int, req IssueRequest) (*Issue, error) {
+	var issue Issue
+	if err := (*service)(s).do(ctx, http.MethodPatch, restapi.RepoPath(repo, "issues/%d", number), req, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// ListComments returns the comments on an issue or pull request.
+func (s *IssuesService) ListComments(ctx context.Context, repo repository.Repository, number int, opts ListOptions) ([]Comment, error) {
+	return list[Comment](ctx, (*service)(s), restapi.RepoPath(repo, "issues/%d/comments", number), nil, opts.Limit)
+}
+
+// CreateComment comments on an issue or pull request.
+func (s *IssuesService) CreateComment(ctx context.Context, repo repository.Repository, number int, body string) (*Comment, error) {
+	req := struct {
+		Body string `json:"body"`
+	}{Body: body}
+	var comment Comment
+	if err := (*service)(s).do(ctx, http.MethodPost, restapi.RepoPath(repo, "issues/%d/comments", number), req, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
@@ -0,0 +1,174 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/api/apitest"
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRepo = repository.Repository{Host: "github.com", Owner: "cli", Name: "cli"}
+
+func newTestClient(t *testing.T, s *apitest.Server) *Client {
+	t.Helper()
+	client, err := api.NewRESTClient(s.ClientOptions())
+	assert.NoError(t, err)
+	return NewClient(client)
+}
+
+func TestIssuesList(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      IssueListOptions
+		wantCount int
+		wantPages int
+	}{
+		{
+			name:      "default limit",
+			wantCount: 30,
+			wantPages: 1,
+		},
+		{
+			name:      "follows next links",
+			opts:      IssueListOptions{ListOptions: ListOptions{Limit: 120}},
+			wantCount: 120,
+			wantPages: 2,
+		},
+		{
+			name:      "stops at last page",
+			opts:      IssueListOptions{ListOptions: ListOptions{Limit: 500}},
+			wantCount: 150,
+			wantPages: 2,
+		},
+		{
+			name:      "state",
+			opts:      IssueListOptions{State: "closed"},
+			wantCount: 5,
+			wantPages: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := apitest.NewServer("token")
+			for i := 1; i <= 155; i++ {
+				state := "open"
+				if i > 150 {
+					state = "closed"
+				}
+				s.AddIssue("cli", "cli", apitest.Issue{Title: fmt.Sprintf("Issue %d", i), State: state})
+			}
+			client := newTestClient(t, s)
+
+			issues, err := client.Issues.List(context.Background(), testRepo, tt.opts)
+			assert.NoError(t, err)
+			assert.Len(t, issues, tt.wantCount)
+			assert.Len(t, s.Calls(), tt.wantPages)
+		})
+	}
+}
+
+func TestIssuesListPullRequests(t *testing.T) {
+	s := apitest.NewServer("token")
+	s.AddIssue("cli", "cli", apitest.Issue{Title: "Bug"})
+	s.AddPullRequest("cli", "cli", apitest.PullRequest{Title: "Fix bug"})
+	client := newTestClient(t, s)
+
+	issues, err := client.Issues.List(context.Background(), testRepo, IssueListOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, issues, 2) {
+		assert.False(t, issues[0].IsPullRequest())
+		assert.True(t, issues[1].IsPullRequest())
+	}
+
+	issue, err := client.Issues.Get(context.Background(), testRepo, 2)
+	assert.NoError(t, err)
+	assert.True(t, issue.IsPullRequest())
+}
+
+func TestIssuesCreateAndUpdate(t *testing.T) {
+	s := apitest.NewServer("token")
+	s.AddRepository(apitest.Repository{Name: "cli", Owner: apitest.User{Login: "cli"}})
+	client := newTestClient(t, s)
+
+	issue, err := client.Issues.Create(context.Background(), testRepo, IssueRequest{
+		Title:  "Bug",
+		Body:   "It is broken",
+		Labels: []string{"bug"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, issue.Number)
+	assert.Equal(t, "open", issue.State)
+	assert.Equal(t, []Label{{Name: "bug"}}, issue.Labels)
+
+	issue, err = client.Issues.Update(context.Background(), testRepo, issue.Number, IssueRequest{State: "closed"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Bug", issue.Title)
+	assert.Equal(t, "It is broken", issue.Body)
+	assert.Equal(t, "closed", issue.State)
+
+	issue, err = client.Issues.Get(context.Background(), testRepo, issue.Number)
+	assert.NoError(t, err)
+	assert.Equal(t, "closed", issue.State)
+}
+
+func TestIssuesErrors(t *testing.T) {
+	s := apitest.NewServer("token")
+	client := newTestClient(t, s)
+
+	_, err := client.Issues.Get(context.Background(), testRepo, 1)
+	var httpErr *api.HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+
+	s.AddRepository(apitest.Repository{Name: "cli", Owner: apitest.User{Login: "cli"}})
+	_, err = client.Issues.Create(context.Background(), testRepo, IssueRequest{})
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, httpErr.StatusCode)
+	assert.Equal(t, "Validation Failed\nIssue.title is missing", httpErr.Message)
+}
+
+func TestIssuesCreateComment(t *testing.T) {
+	s := apitest.NewServer("token")
+	s.HandleFunc("POST", "/repos/{owner}/{repo}/issues/{number}/comments", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id": 1, "body": "LGTM", "user": {"login": "monalisa"}}`)
+	})
+	client := newTestClient(t, s)
+
+	comment, err := client.Issues.CreateComment(context.Background(), testRepo, 12, "LGTM")
+	assert.NoError(t, err)
+	assert.Equal(t, "LGTM", comment.Body)
+	assert.Equal(t, "monalisa", comment.User.Login)
+	calls := s.Calls()
+	assert.Equal(t, "/repos/cli/cli/issues/12/comments", calls[0].Path)
+	assert.JSONEq(t, `{"body": "LGTM"}`, string(calls[0].Body))
+}
+
+func TestLabels(t *testing.T) {
+	s := apitest.NewServer("token")
+	s.HandleFunc("POST", "/repos/{owner}/{repo}/issues/{number}/labels", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[{"name": "bug"}, {"name": "p1"}]`)
+	})
+	s.HandleFunc("DELETE", "/repos/{owner}/{repo}/issues/{number}/labels/{name}", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "needs triage", apitest.PathParam(r, "name"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[]`)
+	})
+	client := newTestClient(t, s)
+
+	labels, err := client.Labels.AddToIssue(context.Background(), testRepo, 12, []string{"p1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Label{{Name: "bug"}, {Name: "p1"}}, labels)
+	assert.JSONEq(t, `{"labels": ["p1"]}`, string(s.Calls()[0].Body))
+
+	err = client.Labels.RemoveFromIssue(context.Background(), testRepo, 12, "needs triage")
+	assert.NoError(t, err)
+}
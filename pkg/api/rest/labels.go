@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/cli/go-gh/v2/internal/restapi"
+	"github.com/cli/go-gh/v2/pkg/repository"
+)
+
+// Label is a label of issues and pull requests.
+type Label struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+// LabelRequest holds the fields of a label to create.
+type LabelRequest struct {
+	Name        string `json:"name"`
+	Color       string `json:"color,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// LabelsService provides methods for labels.
+type LabelsService service
+
+// List returns the labels of a repository.
+func (s *LabelsService) List(ctx context.Context, repo repository.Repository, opts ListOptions) ([]Label, error) {
+	return list[Label](ctx, (*service)(s), restapi.RepoPath(repo, "labels"), nil, opts.Limit)
+}
+
+// Create creates a label.
+func (s *LabelsService) Create(ctx context.Context, repo repository.Repository, req LabelRequest) (*Label, error) {
+	var label Label
+	if err := (*service)(s).do(ctx, http.MethodPost, restapi.RepoPath(repo, "labels"), req, &label); err != nil {
+		return nil, err
+	}
+	return &label, nil
+}
+
+// Delete deletes a label.
+func (s *LabelsService) Delete(ctx context.Context, repo repository.Repository, name string) error {
+	return (*service)(s).do(ctx, http.MethodDelete, restapi.RepoPath(repo, "labels/%s", url.PathEscape(name)), nil, nil)
+}
+
+// AddToIssue adds labels to an issue or pull request,
+// returning all of its labels.
+func (s *LabelsService) AddToIssue(ctx context.Context, repo repository.Repository, number int, names []string) ([]Label, error) {
+	req := struct {
+		Labels []string `json:"labels"`
+	}{Labels: names}
+	var labels []Label
+	if err := (*service)(s).do(ctx, http.MethodPost, restapi.RepoPath(repo, "issues/%d/labels", number), req, &labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// RemoveFromIssue removes a label from an issue or pull request.
+func (s *LabelsService) RemoveFromIssue(ctx context.Context, repo repository.Repository, number int, name string) error {
+	return (*service)(s).do(ctx, http.MethodDelete, restapi.RepoPath(repo, "issues/%d/labels/%s", number, url.PathEscape(name)), nil, nil)
+}
@@ -0,0 +1,121 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cli/go-gh/v2/internal/restapi"
+	"github.com/cli/go-gh/v2/pkg/repository"
+)
+
+// PullRequest is a GitHub pull request.
+type PullRequest struct {
+	ID        int64             `json:"id"`
+	Number    int               `json:"number"`
+	Title     string            `json:"title"`
+	Body      string            `json:"body"`
+	State     string            `json:"state"`
+	Draft     bool              `json:"draft"`
+	Merged    bool              `json:"merged"`
+	User      User              `json:"user"`
+	Head      PullRequestBranch `json:"head"`
+	Base      PullRequestBranch `json:"base"`
+	Labels    []Label           `json:"labels"`
+	Assignees []User            `json:"assignees"`
+	URL       string            `json:"html_url"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	ClosedAt  *time.Time        `json:"closed_at"`
+	MergedAt  *time.Time        `json:"merged_at"`
+}
+
+// PullRequestBranch is the head or base branch of a pull request.
+type PullRequestBranch struct {
+	Label string      `json:"label"`
+	Ref   string      `json:"ref"`
+	SHA   string      `json:"sha"`
+	Repo  *Repository `json:"repo"`
+}
+
+// PullRequestListOptions holds options for listing pull requests.
+type PullRequestListOptions struct {
+	// State is one of "open", "closed", or "all".
+	// Default is "open".
+	State string
+
+	// Head limits the pull requests to those from a branch,
+	// specified as "owner:branch".
+	Head string
+
+	// Base limits the pull requests to those into a branch.
+	Base string
+
+	ListOptions
+}
+
+// PullRequestRequest holds the fields of a pull request to create.
+type PullRequestRequest struct {
+	Title string `json:"title"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Body  string `json:"body,omitempty"`
+	Draft bool   `json:"draft,omitempty"`
+}
+
+// MergeOptions holds options for merging a pull request.
+type MergeOptions struct {
+	// Method is one of "merge", "squash", or "rebase".
+	// Default is "merge".
+	Method string `json:"merge_method,omitempty"`
+
+	// CommitTitle and CommitMessage override the merge commit's message.
+	CommitTitle   string `json:"commit_title,omitempty"`
+	CommitMessage string `json:"commit_message,omitempty"`
+
+	// SHA, if set, must match the head of the pull request
+	// for the merge to succeed.
+	SHA string `json:"sha,omitempty"`
+}
+
+// PullRequestsService provides methods for pull requests.
+type PullRequestsService service
+
+// List returns the pull requests of a repository.
+func (s *PullRequestsService) List(ctx context.Context, repo repository.Repository, opts PullRequestListOptions) ([]PullRequest, error) {
+	query := url.Values{}
+	if opts.State != "" {
+		query.Set("state", opts.State)
+	}
+	if opts.Head != "" {
+		query.Set("head", opts.Head)
+	}
+	if opts.Base != "" {
+		query.Set("base", opts.Base)
+	}
+	return list[PullRequest](ctx, (*service)(s), restapi.RepoPath(repo, "pulls"), query, opts.Limit)
+}
+
+// Get returns a pull request.
+func (s *PullRequestsService) Get(ctx context.Context, repo repository.Repository, number int) (*PullRequest, error) {
+	var pr PullRequest
+	if err := (*service)(s).do(ctx, http.MethodGet, restapi.RepoPath(repo, "pulls/%d", number), nil, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// Create creates a pull request.
+func (s *PullRequestsService) Create(ctx context.Context, repo repository.Repository, req PullRequestRequest) (*PullRequest, error) {
+	var pr PullRequest
+	if err := (*service)(s).do(ctx, http.MethodPost, restapi.RepoPath(repo, "pulls"), req, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// Merge merges a pull request.
+func (s *PullRequestsService) Merge(ctx context.Context, repo repository.Repository, number int, opts MergeOptions) error {
+	return (*service)(s).do(ctx, http.MethodPut, restapi.RepoPath(repo, "pulls/%d/merge", number), opts, nil)
+}
@@ -0,0 +1,64 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api/apitest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullRequestsList(t *testing.T) {
+	s := apitest.NewServer("token")
+	s.AddPullRequest("cli", "cli", apitest.PullRequest{Title: "Feature", Head: apitest.PullRequestRef{Ref: "feature", SHA: "abc"}})
+	s.AddPullRequest("cli", "cli", apitest.PullRequest{Title: "Fix", State: "closed"})
+	client := newTestClient(t, s)
+
+	pulls, err := client.PullRequests.List(context.Background(), testRepo, PullRequestListOptions{State: "all", Base: "main"})
+	assert.NoError(t, err)
+	assert.Len(t, pulls, 2)
+	assert.Equal(t, "feature", pulls[0].Head.Ref)
+	assert.Equal(t, "main", pulls[0].Base.Ref)
+
+	calls := s.Calls()
+	assert.Equal(t, "all", calls[0].Query.Get("state"))
+	assert.Equal(t, "main", calls[0].Query.Get("base"))
+
+	pr, err := client.PullRequests.Get(context.Background(), testRepo, pulls[1].Number)
+	assert.NoError(t, err)
+	assert.Equal(t, "Fix", pr.Title)
+}
+
+func TestPullRequestsCreateAndMerge(t *testing.T) {
+	s := apitest.NewServer("token")
+	s.HandleFunc("POST", "/repos/{owner}/{repo}/pulls", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"number": 7, "title": "Feature", "draft": true, "head": {"ref": "feature"}, "base": {"ref": "trunk"}}`)
+	})
+	s.HandleFunc("PUT", "/repos/{owner}/{repo}/pulls/{number}/merge", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"merged": true}`)
+	})
+	client := newTestClient(t, s)
+
+	pr, err := client.PullRequests.Create(context.Background(), testRepo, PullRequestRequest{
+		Title: "Feature",
+		Head:  "feature",
+		Base:  "trunk",
+		Draft: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, pr.Number)
+	assert.True(t, pr.Draft)
+
+	err = client.PullRequests.Merge(context.Background(), testRepo, pr.Number, MergeOptions{Method: "squash"})
+	assert.NoError(t, err)
+
+	calls := s.Calls()
+	assert.JSONEq(t, `{"title": "Feature", "head": "feature", "base": "trunk", "draft": true}`, string(calls[0].Body))
+	assert.Equal(t, "/repos/cli/cli/pulls/7/merge", calls[1].Path)
+	assert.JSONEq(t, `{"merge_method": "squash"}`, string(calls[1].Body))
+}
@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/cli/go-gh/v2/internal/restapi"
+	"github.com/cli/go-gh/v2/pkg/repository"
+)
+
+// User is a GitHub user or organization.
+type User struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+	Type  string `json:"type"`
+	URL   string `json:"html_url"`
+}
+
+// Repository is a GitHub repository.
+type Repository struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	FullName      string    `json:"full_name"`
+	Owner         User      `json:"owner"`
+	Description   string    `json:"description"`
+	Private       bool      `json:"private"`
+	Fork          bool      `json:"fork"`
+	Archived      bool      `json:"archived"`
+	DefaultBranch string    `json:"default_branch"`
+	URL           string    `json:"html_url"`
+	CloneURL      string    `json:"clone_url"`
+	SSHURL        string    `json:"ssh_url"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	PushedAt      time.Time `json:"pushed_at"`
+}
+
+// Branch is a branch of a repository.
+type Branch struct {
+	Name      string `json:"name"`
+	Protected bool   `json:"protected"`
+	Commit    struct {
+		SHA string `json:"sha"`
+	} `json:"commit"`
+}
+
+// RepositoriesService provides methods for repositories.
+type RepositoriesService service
+
+// Get returns a repository.
+func (s *RepositoriesService) Get(ctx context.Context, repo repository.Repository) (*Repository, error) {
+	var r Repository
+	if err := (*service)(s).do(ctx, http.MethodGet, restapi.RepoPath(repo, ""), nil, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ListBranches returns the branches of a repository.
+func (s *RepositoriesService) ListBranches(ctx context.Context, repo repository.Repository, opts ListOptions) ([]Branch, error) {
+	return list[Branch](ctx, (*service)(s), restapi.RepoPath(repo, "branches"), nil, opts.Limit)
+}
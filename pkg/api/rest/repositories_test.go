@@ -0,0 +1,23 @@
+package rest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api/apitest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositoriesGet(t *testing.T) {
+	s := apitest.NewServer("token")
+	s.AddRepository(apitest.Repository{Name: "cli", Owner: apitest.User{Login: "cli"}, Description: "GitHub CLI"})
+	client := newTestClient(t, s)
+
+	repo, err := client.Repositories.Get(context.Background(), testRepo)
+	assert.NoError(t, err)
+	assert.Equal(t, "cli/cli", repo.FullName)
+	assert.Equal(t, "cli", repo.Owner.Login)
+	assert.Equal(t, "GitHub CLI", repo.Description)
+	assert.Equal(t, "main", repo.DefaultBranch)
+	assert.Equal(t, "/repos/cli/cli", s.Calls()[0].Path)
+}
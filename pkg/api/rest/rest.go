@@ -0,0 +1,80 @@
+// Package rest is an opt-in typed layer over api.RESTClient that models the
+// most commonly used GitHub resources, such as repositories, issues, pull
+// requests, and labels.
+//
+// Every method takes a context and, where applicable, the
+// repository.Repository to operate on. List methods follow the pagination
+// links returned by the API until the requested number of items has been
+// fetched, and failed requests are reported as *api.HTTPError.
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+
+	"github.com/cli/go-gh/v2/internal/restapi"
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Client provides typed access to the GitHub REST API. Each field groups
+// the methods for one kind of resource.
+type Client struct {
+	Repositories *RepositoriesService
+	Issues       *IssuesService
+	PullRequests *PullRequestsService
+	Labels       *LabelsService
+}
+
+// NewClient returns a Client that sends its requests with client.
+func NewClient(client *api.RESTClient) *Client {
+	s := &service{client: client}
+	return &Client{
+		Repositories: (*RepositoriesService)(s),
+		Issues:       (*IssuesService)(s),
+		PullRequests: (*PullRequestsService)(s),
+		Labels:       (*LabelsService)(s),
+	}
+}
+
+// ListOptions holds options for list methods.
+type ListOptions struct {
+	// Limit is the maximum number of items to return.
+	// Default is 30.
+	Limit int
+}
+
+type service struct {
+	client *api.RESTClient
+}
+
+// do sends a request with body encoded as JSON, if not nil,
+// and decodes the response into response, if not nil.
+func (s *service) do(ctx context.Context, method, path string, body, response interface{}) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+	if response == nil {
+		resp, err := s.client.RequestWithContext(ctx, method, path, r)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+	return s.client.DoWithContext(ctx, method, path, r, response)
+}
+
+// list fetches pages of path, which respond with a JSON array of items,
+// until limit items have been returned or there are no more pages.
+func list[T any](ctx context.Context, s *service, path string, query url.Values, limit int) ([]T, error) {
+	return restapi.List(ctx, s.client, path, query, limit, func(page *[]T) []T {
+		return *page
+	})
+}
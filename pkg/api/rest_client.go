@@ -8,7 +8,9 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/cli/go-gh/v2/pkg/asciisanitizer"
 	"github.com/cli/go-gh/v2/pkg/auth"
+	"golang.org/x/text/transform"
 )
 
 // RESTClient wraps methods for the different types of
@@ -76,6 +78,71 @@ func (c *RESTClient) Request(method string, path string, body io.Reader) (*http.
 	return c.RequestWithContext(context.Background(), method, path, body)
 }
 
+// StreamOptions holds options for streaming a response body.
+type StreamOptions struct {
+	// Offset is the number of bytes at the start of the response body to skip.
+	// A Range request is sent so that servers supporting it do not send the
+	// skipped bytes. Requesting an offset at or past the end of the body
+	// returns an empty body.
+	Offset int64
+
+	// Sanitize replaces ASCII control characters in text response bodies,
+	// such as text/plain logs, with inert characters that are safe for display
+	// in a terminal. Other response bodies, such as archives, are not modified.
+	// JSON response bodies are always sanitized.
+	Sanitize bool
+}
+
+// StreamWithContext issues a request with type specified by method to the
+// specified path with the specified body.
+// The response body is returned without being buffered, unless the client
+// deduplicates requests, and it is the responsibility of the caller to close it.
+func (c *RESTClient) StreamWithContext(ctx context.Context, method string, path string, body io.Reader, opts StreamOptions) (io.ReadCloser, error) {
+	url := restURL(c.host, path)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", opts.Offset))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Offset > 0 && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		resp.Body.Close()
+		return http.NoBody, nil
+	}
+
+	success := resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !success {
+		defer resp.Body.Close()
+		return nil, HandleHTTPError(resp)
+	}
+
+	// Skip the offset ourselves if the server ignored the Range header.
+	if opts.Offset > 0 && resp.StatusCode != http.StatusPartialContent {
+		if _, err := io.CopyN(io.Discard, resp.Body, opts.Offset); err != nil && err != io.EOF {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+
+	var r io.Reader = resp.Body
+	if opts.Sanitize && textTypeRE.MatchString(resp.Header.Get(contentType)) {
+		r = transform.NewReader(r, &asciisanitizer.Sanitizer{})
+	}
+	return &readCloser{Reader: r, Closer: resp.Body}, nil
+}
+
+// Stream wraps StreamWithContext with context.Background.
+func (c *RESTClient) Stream(method string, path string, body io.Reader, opts StreamOptions) (io.ReadCloser, error) {
+	return c.StreamWithContext(context.Background(), method, path, body, opts)
+}
+
 // DoWithContext issues a request with type specified by method to the
 // specified path with the specified body.
 // The response is populated into the response argument.
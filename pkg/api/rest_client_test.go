@@ -448,6 +448,119 @@ func TestRESTClientRequestWithContext(t *testing.T) {
 	}
 }
 
+func TestRESTClientStream(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       StreamOptions
+		httpMocks  func()
+		wantBody   string
+		wantErrMsg string
+	}{
+		{
+			name: "streams body",
+			httpMocks: func() {
+				gock.New("https://api.github.com").
+					Get("/some/path").
+					Reply(200).
+					SetHeader("Content-Type", "text/plain").
+					BodyString("line 1\n\x1b[31mline 2\n")
+			},
+			wantBody: "line 1\n\x1b[31mline 2\n",
+		},
+		{
+			name: "sanitizes text body",
+			opts: StreamOptions{Sanitize: true},
+			httpMocks: func() {
+				gock.New("https://api.github.com").
+					Get("/some/path").
+					Reply(200).
+					SetHeader("Content-Type", "text/plain").
+					BodyString("line 1\n\x1b[31mline 2\n")
+			},
+			wantBody: "line 1\n^[[31mline 2\n",
+		},
+		{
+			name: "does not sanitize binary body",
+			opts: StreamOptions{Sanitize: true},
+			httpMocks: func() {
+				gock.New("https://api.github.com").
+					Get("/some/path").
+					Reply(200).
+					SetHeader("Content-Type", "application/zip").
+					BodyString("PK\x03\x04\x1b\xff")
+			},
+			wantBody: "PK\x03\x04\x1b\xff",
+		},
+		{
+			name: "requests range from offset",
+			opts: StreamOptions{Offset: 7},
+			httpMocks: func() {
+				gock.New("https://api.github.com").
+					Get("/some/path").
+					MatchHeader("Range", "bytes=7-").
+					Reply(206).
+					BodyString("line 2\n")
+			},
+			wantBody: "line 2\n",
+		},
+		{
+			name: "skips offset when range is ignored",
+			opts: StreamOptions{Offset: 7},
+			httpMocks: func() {
+				gock.New("https://api.github.com").
+					Get("/some/path").
+					Reply(200).
+					BodyString("line 1\nline 2\n")
+			},
+			wantBody: "line 2\n",
+		},
+		{
+			name: "offset past end of body",
+			opts: StreamOptions{Offset: 14},
+			httpMocks: func() {
+				gock.New("https://api.github.com").
+					Get("/some/path").
+					Reply(416)
+			},
+			wantBody: "",
+		},
+		{
+			name: "fail",
+			httpMocks: func() {
+				gock.New("https://api.github.com").
+					Get("/some/path").
+					Reply(404).
+					JSON(`{"message": "Not Found"}`)
+			},
+			wantErrMsg: "HTTP 404: Not Found (https://api.github.com/some/path)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(gock.Off)
+			tt.httpMocks()
+			client, _ := NewRESTClient(ClientOptions{
+				Host:      "github.com",
+				AuthToken: "token",
+				Transport: http.DefaultTransport,
+			})
+
+			body, err := client.Stream(http.MethodGet, "some/path", nil, tt.opts)
+			if tt.wantErrMsg != "" {
+				assert.EqualError(t, err, tt.wantErrMsg)
+			} else {
+				assert.NoError(t, err)
+				b, err := io.ReadAll(body)
+				assert.NoError(t, err)
+				assert.NoError(t, body.Close())
+				assert.Equal(t, tt.wantBody, string(b))
+			}
+			assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+		})
+	}
+}
+
 func TestRestPrefix(t *testing.T) {
 	tests := []struct {
 		name         string
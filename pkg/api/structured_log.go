@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// logInstrumenter is an Instrumenter that writes one structured
+// log record for each finished request.
+type logInstrumenter struct {
+	logger   *slog.Logger
+	redactor *redactor
+	verbose  bool
+}
+
+func newLogInstrumenter(w io.Writer, verbose bool, redactor *redactor) *logInstrumenter {
+	return &logInstrumenter{
+		logger:   slog.New(slog.NewJSONHandler(w, nil)),
+		redactor: redactor,
+		verbose:  verbose,
+	}
+}
+
+func (li *logInstrumenter) RequestStarted(ctx context.Context, _ *RequestEvent) context.Context {
+	return ctx
+}
+
+func (li *logInstrumenter) RequestFinished(ctx context.Context, e *RequestEvent) {
+	cache := "miss"
+	if e.CacheHit {
+		cache = "hit"
+	}
+	attrs := []slog.Attr{
+		slog.String("method", e.Method),
+		slog.String("url", e.URL.String()),
+		slog.String("path_template", e.PathTemplate),
+		slog.Int64("duration_ms", e.Duration.Milliseconds()),
+		slog.Int64("request_bytes", e.RequestBytes),
+		slog.Int64("response_bytes", e.ResponseBytes),
+		slog.String("cache", cache),
+	}
+	if e.StatusCode != 0 {
+		attrs = append(attrs, slog.Int("status", e.StatusCode))
+	}
+	if e.GraphQLOperation != "" {
+		attrs = append(attrs, slog.String("graphql_operation", e.GraphQLOperation))
+	}
+	if e.Retries > 0 {
+		attrs = append(attrs, slog.Int("retries", e.Retries))
+	}
+	if e.RateLimitRemaining >= 0 {
+		attrs = append(attrs, slog.Int("rate_limit_remaining", e.RateLimitRemaining))
+	}
+	if e.Err != nil {
+		attrs = append(attrs, slog.String("error", e.Err.Error()))
+	}
+	if li.verbose {
+		attrs = append(attrs,
+			headerAttr("request_headers", li.redactor.header(e.RequestHeader)),
+			headerAttr("response_headers", li.redactor.header(e.ResponseHeader)))
+	}
+	li.logger.LogAttrs(ctx, slog.LevelInfo, "http request", attrs...)
+}
+
+func headerAttr(key string, h http.Header) slog.Attr {
+	attrs := make([]any, 0, len(h))
+	for k, v := range h {
+		attrs = append(attrs, slog.String(k, strings.Join(v, ", ")))
+	}
+	return slog.Group(key, attrs...)
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructuredLog(t *testing.T) {
+	fakeHTTP := tripper{
+		roundTrip: func(req *http.Request) (*http.Response, error) {
+			header := http.Header{}
+			header.Set(contentType, "application/json")
+			header.Set(rateLimitRemaining, "4999")
+			header.Set("Set-Cookie", "session=secret")
+			return &http.Response{
+				StatusCode: 200,
+				Header:     header,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"data": {}}`)),
+			}, nil
+		},
+	}
+
+	log := &bytes.Buffer{}
+	client, err := NewHTTPClient(ClientOptions{
+		Host:           "github.com",
+		AuthToken:      "token",
+		Transport:      fakeHTTP,
+		EnableCache:    true,
+		CacheDir:       filepath.Join(t.TempDir(), "gh-cli-cache"),
+		Log:            log,
+		LogJSON:        true,
+		LogVerboseHTTP: true,
+	})
+	assert.NoError(t, err)
+
+	do := func(method, url, body string) {
+		req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+		assert.NoError(t, err)
+		res, err := client.Do(req)
+		assert.NoError(t, err)
+		_, err = io.ReadAll(res.Body)
+		assert.NoError(t, err)
+		res.Body.Close()
+	}
+
+	query := `{"query": "query RepositoryInfo($owner: String!) { repository { id } }", "variables": {"owner": "cli"}}`
+	do("POST", "https://api.github.com/graphql", query)
+	do("POST", "https://api.github.com/graphql", query)
+
+	var records []map[string]interface{}
+	scanner := bufio.NewScanner(log)
+	for scanner.Scan() {
+		var record map[string]interface{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	assert.Len(t, records, 2)
+
+	first := records[0]
+	assert.Equal(t, "http request", first["msg"])
+	assert.Equal(t, "POST", first["method"])
+	assert.Equal(t, "https://api.github.com/graphql", first["url"])
+	assert.Equal(t, "/graphql", first["path_template"])
+	assert.Equal(t, float64(200), first["status"])
+	assert.Equal(t, "RepositoryInfo", first["graphql_operation"])
+	assert.Equal(t, float64(len(query)), first["request_bytes"])
+	assert.Equal(t, float64(12), first["response_bytes"])
+	assert.Equal(t, "miss", first["cache"])
+	assert.Equal(t, float64(4999), first["rate_limit_remaining"])
+	assert.Contains(t, first, "duration_ms")
+	requestHeaders := first["request_headers"].(map[string]interface{})
+	assert.Equal(t, "token [REDACTED]", requestHeaders["Authorization"])
+	assert.Equal(t, jsonContentType, requestHeaders["Content-Type"])
+	responseHeaders := first["response_headers"].(map[string]interface{})
+	assert.Equal(t, "[REDACTED]", responseHeaders["Set-Cookie"])
+
+	assert.Equal(t, "hit", records[1]["cache"])
+}
+
+func TestGraphQLOperationName(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		body   string
+		want   string
+	}{
+		{
+			name:   "operation name",
+			method: "POST",
+			url:    "https://api.github.com/graphql",
+			body:   `{"query": "query A { viewer { login } } query B { viewer { id } }", "operationName": "B"}`,
+			want:   "B",
+		},
+		{
+			name:   "named query",
+			method: "POST",
+			url:    "https://example.com/api/graphql",
+			body:   `{"query": "  mutation AddStar($id: ID!) { addStar }"}`,
+			want:   "AddStar",
+		},
+		{
+			name:   "anonymous query",
+			method: "POST",
+			url:    "https://api.github.com/graphql",
+			body:   `{"query": "{ viewer { login } }"}`,
+			want:   "",
+		},
+		{
+			name:   "not graphql",
+			method: "POST",
+			url:    "https://api.github.com/repos/cli/cli/issues",
+			body:   `{"query": "query A { viewer { login } }"}`,
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, graphQLOperationName(req))
+			body, err := io.ReadAll(req.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.body, string(body))
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/config"
+)
+
+// Config keys for the transport options. Each key is read from the
+// hosts.<host> section of the configuration first, and then from the
+// top level of the configuration.
+const (
+	caCertKey        = "http_ca_cert"
+	clientCertKey    = "http_client_cert"
+	clientKeyKey     = "http_client_key"
+	proxyKey         = "http_proxy"
+	tlsMinVersionKey = "http_tls_min_version"
+	unixSocketKey    = "http_unix_socket"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// resolveTransportOptions fills in the transport options that were not
+// provided by the consumer from the configuration for the host.
+func resolveTransportOptions(opts ClientOptions, cfg *config.Config) ClientOptions {
+	if cfg == nil {
+		return opts
+	}
+	values := []struct {
+		key   string
+		value *string
+	}{
+		{caCertKey, &opts.CACertFile},
+		{clientCertKey, &opts.ClientCertFile},
+		{clientKeyKey, &opts.ClientKeyFile},
+		{proxyKey, &opts.Proxy},
+		{tlsMinVersionKey, &opts.TLSMinVersion},
+	}
+	for _, v := range values {
+		if *v.value == "" {
+			*v.value = hostConfigValue(cfg, opts.Host, v.key)
+		}
+	}
+	return opts
+}
+
+// hostConfigValue returns the value of key for host, falling back to
+// the top level value of key.
+func hostConfigValue(cfg *config.Config, host, key string) string {
+	entry, _ := cfg.Lookup(host, key)
+	return entry.Value
+}
+
+func needsTLSConfig(opts ClientOptions) bool {
+	return opts.CACertFile != "" ||
+		opts.ClientCertFile != "" ||
+		opts.ClientKeyFile != "" ||
+		opts.TLSMinVersion != ""
+}
+
+// newBaseTransport builds the transport at the base of the API request
+// transport chain, applying the TLS, proxy, and Unix domain socket options.
+func newBaseTransport(opts ClientOptions) (http.RoundTripper, error) {
+	// Requests over a Unix domain socket only use TLS if it is configured.
+	var tlsConfig *tls.Config
+	if needsTLSConfig(opts) {
+		var err error
+		if tlsConfig, err = newTLSConfig(opts); err != nil {
+			return nil, err
+		}
+	}
+
+	var transport http.RoundTripper
+	switch {
+	case opts.UnixDomainSocket != "":
+		transport = newUnixDomainSocketRoundTripper(opts.UnixDomainSocket, tlsConfig)
+	case tlsConfig != nil || opts.Proxy != "":
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.TLSClientConfig = tlsConfig
+		if opts.Proxy != "" {
+			proxyURL, err := url.Parse(opts.Proxy)
+			if err != nil || proxyURL.Host == "" {
+				return nil, fmt.Errorf("invalid proxy URL %q", opts.Proxy)
+			}
+			t.Proxy = http.ProxyURL(proxyURL)
+		}
+		transport = t
+	default:
+		transport = http.DefaultTransport
+	}
+
+	if len(opts.UnixDomainSockets) == 0 {
+		return transport, nil
+	}
+	hrt := &hostSocketRoundTripper{fallback: transport}
+	for host, socketPath := range opts.UnixDomainSockets {
+		if socketPath == "" {
+			continue
+		}
+		hrt.routes = append(hrt.routes, hostSocketRoute{
+			host: host,
+			rt:   newUnixDomainSocketRoundTripper(socketPath, tlsConfig),
+		})
+	}
+	// Match the most specific host first so that a socket for ghe.example.com
+	// takes precedence over one for example.com.
+	sort.Slice(hrt.routes, func(i, j int) bool {
+		return len(hrt.routes[i].host) > len(hrt.routes[j].host)
+	})
+	return hrt, nil
+}
+
+func newTLSConfig(opts ClientOptions) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+
+	if opts.TLSMinVersion != "" {
+		v := strings.TrimPrefix(strings.ToLower(opts.TLSMinVersion), "tls")
+		version, ok := tlsVersions[strings.TrimSpace(v)]
+		if !ok {
+			return nil, fmt.Errorf("invalid TLS minimum version %q", opts.TLSMinVersion)
+		}
+		tlsConfig.MinVersion = version
+	}
+
+	if opts.CACertFile != "" {
+		pem, err := os.ReadFile(opts.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificates: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no CA certificates found in %s", opts.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if opts.ClientCertFile != "" || opts.ClientKeyFile != "" {
+		if opts.ClientCertFile == "" || opts.ClientKeyFile == "" {
+			return nil, fmt.Errorf("both a client certificate and a client key are required")
+		}
+		cert, err := tls.LoadX509KeyPair(opts.ClientCertFile, opts.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// newUnixDomainSocketRoundTripper builds a transport that routes every request
+// through the socket, keeping connections open between requests. Without a TLS
+// configuration, requests are sent over the socket as plain HTTP. With one, a
+// TLS connection is established over the socket for HTTPS requests.
+func newUnixDomainSocketRoundTripper(socketPath string, tlsConfig *tls.Config) http.RoundTripper {
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", socketPath)
+	}
+
+	dialTLS := dial
+	if tlsConfig != nil {
+		dialTLS = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := dial(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			cfg := tlsConfig.Clone()
+			if cfg.ServerName == "" {
+				cfg.ServerName, _, _ = net.SplitHostPort(addr)
+			}
+			tlsConn := tls.Client(conn, cfg)
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				conn.Close()
+				return nil, err
+			}
+			return tlsConn, nil
+		}
+	}
+
+	return &http.Transport{
+		DialContext:         dial,
+		DialTLSContext:      dialTLS,
+		IdleConnTimeout:     90 * time.Second,
+		MaxIdleConnsPerHost: 10,
+	}
+}
+
+// hostSocketRoundTripper routes requests for specific hosts through their
+// Unix domain sockets, and all other requests through the fallback.
+type hostSocketRoundTripper struct {
+	fallback http.RoundTripper
+	routes   []hostSocketRoute
+}
+
+type hostSocketRoute struct {
+	host string
+	rt   http.RoundTripper
+}
+
+func (hrt *hostSocketRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	for _, route := range hrt.routes {
+		if isSameDomain(req.URL.Hostname(), route.host) {
+			return route.rt.RoundTrip(req)
+		}
+	}
+	return hrt.fallback.RoundTrip(req)
+}
+
+// hostUnixDomainSockets returns the http_unix_socket configured for each host.
+func hostUnixDomainSockets(cfg *config.Config) map[string]string {
+	hosts, err := cfg.Keys([]string{"hosts"})
+	if err != nil {
+		return nil
+	}
+	var sockets map[string]string
+	for _, host := range hosts {
+		socketPath, err := cfg.Get([]string{"hosts", host, unixSocketKey})
+		if err != nil || socketPath == "" {
+			continue
+		}
+		if sockets == nil {
+			sockets = map[string]string{}
+		}
+		sockets[host] = socketPath
+	}
+	return sockets
+}
@@ -0,0 +1,392 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveTransportOptions(t *testing.T) {
+	cfg := config.ReadFromString(`
+http_proxy: http://proxy.example.com:8080
+http_tls_min_version: "1.2"
+hosts:
+  github.com:
+    oauth_token: token
+  ghe.example.com:
+    oauth_token: token
+    http_proxy: http://ghe-proxy.example.com:3128
+    http_ca_cert: /etc/ssl/ghe.pem
+    http_client_cert: /etc/ssl/client.pem
+    http_client_key: /etc/ssl/client.key
+`)
+
+	opts := resolveTransportOptions(ClientOptions{Host: "github.com"}, cfg)
+	assert.Equal(t, "http://proxy.example.com:8080", opts.Proxy)
+	assert.Equal(t, "1.2", opts.TLSMinVersion)
+	assert.Equal(t, "", opts.CACertFile)
+
+	opts = resolveTransportOptions(ClientOptions{Host: "ghe.example.com", TLSMinVersion: "1.3"}, cfg)
+	assert.Equal(t, "http://ghe-proxy.example.com:3128", opts.Proxy)
+	assert.Equal(t, "1.3", opts.TLSMinVersion)
+	assert.Equal(t, "/etc/ssl/ghe.pem", opts.CACertFile)
+	assert.Equal(t, "/etc/ssl/client.pem", opts.ClientCertFile)
+	assert.Equal(t, "/etc/ssl/client.key", opts.ClientKeyFile)
+}
+
+func TestNewHTTPClientTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, clientCert := writeClientCertificate(t, dir)
+
+	var peerCertificates int
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		peerCertificates = len(r.TLS.PeerCertificates)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server.TLS = &tls.Config{ClientAuth: tls.VerifyClientCertIfGiven, ClientCAs: clientCAs, MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	caFile := writeServerCertificate(t, dir, server.Certificate())
+
+	tests := []struct {
+		name      string
+		opts      ClientOptions
+		wantErr   string
+		wantPeers int
+	}{
+		{
+			name:    "untrusted server",
+			opts:    ClientOptions{},
+			wantErr: "certificate signed by unknown authority",
+		},
+		{
+			name: "custom CA",
+			opts: ClientOptions{CACertFile: caFile},
+		},
+		{
+			name:      "client certificate",
+			opts:      ClientOptions{CACertFile: caFile, ClientCertFile: certFile, ClientKeyFile: keyFile},
+			wantPeers: 1,
+		},
+		{
+			name:    "minimum TLS version",
+			opts:    ClientOptions{CACertFile: caFile, TLSMinVersion: "1.3"},
+			wantErr: "protocol version not supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peerCertificates = 0
+			tt.opts.Host = "github.com"
+			tt.opts.AuthToken = "token"
+			tt.opts.LogIgnoreEnv = true
+			client, err := NewHTTPClient(tt.opts)
+			assert.NoError(t, err)
+
+			res, err := client.Get(server.URL)
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			res.Body.Close()
+			assert.Equal(t, http.StatusNoContent, res.StatusCode)
+			assert.Equal(t, tt.wantPeers, peerCertificates)
+		})
+	}
+}
+
+func TestNewHTTPClientUnixDomainSocketTLS(t *testing.T) {
+	dir, err := os.MkdirTemp("", "gh")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	listener, err := net.Listen("unix", filepath.Join(dir, "gh.sock"))
+	assert.NoError(t, err)
+
+	var host string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.Listener = listener
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	caFile := writeServerCertificate(t, dir, server.Certificate())
+
+	client, err := NewHTTPClient(ClientOptions{
+		Host:             "github.com",
+		AuthToken:        "token",
+		UnixDomainSocket: listener.Addr().String(),
+		CACertFile:       caFile,
+		LogIgnoreEnv:     true,
+	})
+	assert.NoError(t, err)
+
+	// The test server certificate is valid for example.com.
+	res, err := client.Get("https://example.com/user")
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	assert.Equal(t, "example.com", host)
+}
+
+func TestNewHTTPClientUnixDomainSocketWithProxy(t *testing.T) {
+	dir, err := os.MkdirTemp("", "gh")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	listen := func(name string) string {
+		listener, err := net.Listen("unix", filepath.Join(dir, name+".sock"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		server.Listener = listener
+		server.Start()
+		t.Cleanup(server.Close)
+		return listener.Addr().String()
+	}
+
+	tests := []struct {
+		name string
+		opts ClientOptions
+	}{
+		{
+			name: "socket",
+			opts: ClientOptions{UnixDomainSocket: listen("gh")},
+		},
+		{
+			name: "socket for host",
+			opts: ClientOptions{UnixDomainSockets: map[string]string{"github.com": listen("github")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The proxy is not used and does not enable TLS over the plain HTTP socket.
+			opts := tt.opts
+			opts.Host = "github.com"
+			opts.AuthToken = "token"
+			opts.Proxy = "http://proxy.invalid:3128"
+			opts.LogIgnoreEnv = true
+			client, err := NewHTTPClient(opts)
+			assert.NoError(t, err)
+
+			res, err := client.Get("https://api.github.com/user")
+			if assert.NoError(t, err) {
+				res.Body.Close()
+				assert.Equal(t, http.StatusNoContent, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClientProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(proxy.Close)
+
+	client, err := NewHTTPClient(ClientOptions{
+		Host:         "github.localhost",
+		AuthToken:    "token",
+		Proxy:        proxy.URL,
+		LogIgnoreEnv: true,
+	})
+	assert.NoError(t, err)
+
+	res, err := client.Get("http://api.github.localhost/user")
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, "http://api.github.localhost/user", proxied)
+}
+
+func TestNewHTTPClientTransportErrors(t *testing.T) {
+	dir := t.TempDir()
+	certFile, _, _ := writeClientCertificate(t, dir)
+	emptyFile := filepath.Join(dir, "empty.pem")
+	assert.NoError(t, os.WriteFile(emptyFile, nil, 0600))
+
+	tests := []struct {
+		name    string
+		opts    ClientOptions
+		wantErr string
+	}{
+		{
+			name:    "invalid TLS version",
+			opts:    ClientOptions{TLSMinVersion: "1.4"},
+			wantErr: `invalid TLS minimum version "1.4"`,
+		},
+		{
+			name:    "missing CA file",
+			opts:    ClientOptions{CACertFile: filepath.Join(dir, "missing.pem")},
+			wantErr: "failed to read CA certificates: open " + filepath.Join(dir, "missing.pem") + ": no such file or directory",
+		},
+		{
+			name:    "CA file without certificates",
+			opts:    ClientOptions{CACertFile: emptyFile},
+			wantErr: "no CA certificates found in " + emptyFile,
+		},
+		{
+			name:    "client certificate without key",
+			opts:    ClientOptions{ClientCertFile: certFile},
+			wantErr: "both a client certificate and a client key are required",
+		},
+		{
+			name:    "invalid proxy",
+			opts:    ClientOptions{Proxy: "://proxy"},
+			wantErr: `invalid proxy URL "://proxy"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Host = "github.com"
+			tt.opts.AuthToken = "token"
+			_, err := NewHTTPClient(tt.opts)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func writeServerCertificate(t *testing.T, dir string, cert *x509.Certificate) string {
+	t.Helper()
+	path := filepath.Join(dir, "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func writeClientCertificate(t *testing.T, dir string) (string, string, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile, cert
+}
+
+func TestNewHTTPClientUnixDomainSockets(t *testing.T) {
+	dir, err := os.MkdirTemp("", "gh")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	var mu sync.Mutex
+	served := map[string][]string{}
+	connections := map[string]int{}
+	listen := func(name string) string {
+		listener, err := net.Listen("unix", filepath.Join(dir, name+".sock"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			served[name] = append(served[name], r.Host+r.URL.Path)
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		server.Listener = listener
+		server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				mu.Lock()
+				connections[name]++
+				mu.Unlock()
+			}
+		}
+		server.Start()
+		t.Cleanup(server.Close)
+		return listener.Addr().String()
+	}
+
+	fallback := tripper{
+		roundTrip: func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			served["fallback"] = append(served["fallback"], req.URL.Host+req.URL.Path)
+			mu.Unlock()
+			return &http.Response{StatusCode: http.StatusNoContent, Header: http.Header{}, Body: http.NoBody, Request: req}, nil
+		},
+	}
+	transport, err := newBaseTransport(ClientOptions{
+		UnixDomainSockets: map[string]string{
+			"example.com":     listen("example"),
+			"ghe.example.com": listen("ghe"),
+		},
+	})
+	assert.NoError(t, err)
+	transport.(*hostSocketRoundTripper).fallback = fallback
+	client := &http.Client{Transport: transport}
+
+	for _, u := range []string{
+		"http://ghe.example.com/api/v3/user",
+		"http://ghe.example.com/api/graphql",
+		"http://ghe.example.com/api/v3/repos/cli/cli",
+		"http://api.example.com/user",
+		"https://api.github.com/user",
+	} {
+		res, err := client.Get(u)
+		assert.NoError(t, err)
+		res.Body.Close()
+	}
+
+	assert.Equal(t, map[string][]string{
+		"ghe":      {"ghe.example.com/api/v3/user", "ghe.example.com/api/graphql", "ghe.example.com/api/v3/repos/cli/cli"},
+		"example":  {"api.example.com/user"},
+		"fallback": {"api.github.com/user"},
+	}, served)
+	// Connections over the socket are kept alive between requests.
+	assert.Equal(t, map[string]int{"ghe": 1, "example": 1}, connections)
+}
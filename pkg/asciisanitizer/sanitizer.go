@@ -6,7 +6,6 @@ package asciisanitizer
 
 import (
 	"bytes"
-	"errors"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -24,7 +23,9 @@ type Sanitizer struct {
 }
 
 // Transform uses a sliding window algorithm to detect C0 and C1 control characters as they are read and replaces
-// them with equivalent inert characters. Bytes that are not part of a control character are not modified.
+// them with equivalent inert characters. Bytes that are not valid UTF-8 are replaced with the Unicode replacement
+// character, since some terminals interpret single bytes such as \x9B as control characters. Other bytes are
+// not modified.
 func (t *Sanitizer) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
 	transfer := func(write, read []byte) error {
 		readLength := len(read)
@@ -48,13 +49,16 @@ func (t *Sanitizer) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err
 		}
 		r, size := utf8.DecodeRune(src)
 		if r == utf8.RuneError && size < 2 {
-			if !atEOF {
+			if !atEOF && !utf8.FullRune(src) {
 				err = transform.ErrShortSrc
 				return
-			} else {
-				err = errors.New("invalid UTF-8 string")
+			}
+			err = transfer([]byte(string(utf8.RuneError)), src[:1])
+			if err != nil {
 				return
 			}
+			t.addEscape = false
+			continue
 		}
 		// Replace C0 and C1 control characters.
 		if unicode.IsControl(r) {
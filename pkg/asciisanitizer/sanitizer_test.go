@@ -78,6 +78,17 @@ func TestSanitizerTransform(t *testing.T) {
 			input: "80\xC2\x80",
 			want:  "80^@",
 		},
+		{
+			name:  "Invalid UTF-8",
+			input: "a\xffb \x9B[31m c\xC2 \xE2\x82",
+			want:  "a\uFFFDb \uFFFD[31m c\uFFFD \uFFFD\uFFFD",
+		},
+		{
+			name:  "JSON with invalid UTF-8",
+			json:  true,
+			input: `"a` + "\xff" + `\u001B"`,
+			want:  `"a` + "\uFFFD" + `^["`,
+		},
 		{
 			name: "C1 control characters",
 			input: "80\xC2\x80 81\xC2\x81 82\xC2\x82 83\xC2\x83 84\xC2\x84 85\xC2\x85 86\xC2\x86 87\xC2\x87 88\xC2\x88 89\xC2\x89 " +
@@ -28,6 +28,7 @@ var (
 	cfg     *Config
 	once    sync.Once
 	loadErr error
+	cfgMu   sync.RWMutex
 )
 
 // Config is a in memory representation of the gh configuration files.
@@ -129,6 +130,12 @@ func (c *Config) Set(keys []string, value string) {
 	m.SetEntry(keys[len(keys)-1], val)
 }
 
+func (c *Config) replace(other *Config) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = other.entries
+}
+
 func (c *Config) deepCopy() *Config {
 	return ReadFromString(c.entries.String())
 }
@@ -140,11 +147,47 @@ func (c *Config) deepCopy() *Config {
 // an empty configuration will be returned.
 var Read = func(fallback *Config) (*Config, error) {
 	once.Do(func() {
+		cfgMu.Lock()
+		defer cfgMu.Unlock()
 		cfg, loadErr = load(generalConfigFile(), hostsConfigFile(), fallback)
 	})
+	cfgMu.RLock()
+	defer cfgMu.RUnlock()
 	return cfg, loadErr
 }
 
+// Reload reads gh configuration files from the local file system again,
+// replacing the configuration returned by Read. If a configuration was
+// read before, its contents are replaced in place so that existing
+// references observe the changes, and any modifications that have not
+// been written are discarded. If the files cannot be read, the error is
+// returned and the configuration returned by Read is left unchanged.
+// The fallback is used as it is by Read.
+func Reload(fallback *Config) (*Config, error) {
+	c, err := load(generalConfigFile(), hostsConfigFile(), fallback)
+	if err != nil {
+		return nil, err
+	}
+	return setCached(c), nil
+}
+
+// setCached replaces the configuration returned by Read with c,
+// updating a previously read configuration in place.
+func setCached(c *Config) *Config {
+	// Make sure that a pending first Read does not replace the configuration.
+	once.Do(func() {})
+
+	cfgMu.Lock()
+	defer cfgMu.Unlock()
+	if cfg != nil {
+		cfg.replace(c)
+	} else {
+		cfg = c
+	}
+	loadErr = nil
+	return cfg
+}
+
 // ReadFromString takes a yaml string and returns a Config.
 func ReadFromString(str string) *Config {
 	m, _ := mapFromString(str)
@@ -156,8 +199,18 @@ func ReadFromString(str string) *Config {
 
 // Write gh configuration files to the local file system.
 // It will only write gh configuration files that have been modified
-// since last being read.
+// since last being read. The files are replaced atomically while holding
+// the configuration lock, see Lock.
 func Write(c *Config) error {
+	l, err := Lock(DefaultLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer l.Unlock()
+	return write(c)
+}
+
+func write(c *Config) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	hosts, err := c.entries.FindEntry("hosts")
@@ -318,19 +371,45 @@ func readFile(filename string) ([]byte, error) {
 	return data, nil
 }
 
+// writeFile atomically replaces the contents of filename by writing data to a
+// temporary file in the same directory and renaming it over filename, so that
+// readers never observe a partially written file. The permissions of an
+// existing file are preserved, and if filename is a symbolic link the file it
+// points to is replaced.
 func writeFile(filename string, data []byte) (writeErr error) {
+	if target, err := filepath.EvalSymlinks(filename); err == nil {
+		filename = target
+	}
 	if writeErr = os.MkdirAll(filepath.Dir(filename), 0771); writeErr != nil {
 		return
 	}
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(filename); err == nil {
+		mode = info.Mode().Perm()
+	}
+
 	var file *os.File
-	if file, writeErr = os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600); writeErr != nil {
+	if file, writeErr = os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp-*"); writeErr != nil {
 		return
 	}
 	defer func() {
-		if err := file.Close(); writeErr == nil && err != nil {
-			writeErr = err
+		if writeErr != nil {
+			_ = file.Close()
+			_ = os.Remove(file.Name())
 		}
 	}()
-	_, writeErr = file.Write(data)
+	if _, writeErr = file.Write(data); writeErr != nil {
+		return
+	}
+	if writeErr = file.Sync(); writeErr != nil {
+		return
+	}
+	if writeErr = file.Chmod(mode); writeErr != nil {
+		return
+	}
+	if writeErr = file.Close(); writeErr != nil {
+		return
+	}
+	writeErr = os.Rename(file.Name(), filename)
 	return
 }
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	lockFileName = "config.lock"
+
+	// lockRetryInterval is how often an unavailable lock is retried.
+	lockRetryInterval = 50 * time.Millisecond
+)
+
+// DefaultLockTimeout is how long Write and Update wait to acquire the configuration lock.
+const DefaultLockTimeout = 10 * time.Second
+
+// lockHeld is a semaphore that is full while this process holds the
+// configuration lock, since advisory file locks are not guaranteed to
+// exclude other callers within the same process.
+var lockHeld = make(chan struct{}, 1)
+
+// LockTimeoutError represents an error when the configuration lock
+// could not be acquired in time.
+type LockTimeoutError struct {
+	Path    string
+	Timeout time.Duration
+}
+
+// Allow LockTimeoutError to satisfy error interface.
+func (e *LockTimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %s waiting for another gh process to release the configuration lock %s", e.Timeout, e.Path)
+}
+
+// FileLock is an advisory lock on the gh configuration files that is
+// shared by cooperating processes.
+type FileLock struct {
+	f *os.File
+}
+
+// Lock acquires the configuration lock, waiting up to timeout for another
+// caller to release it. The lock is an operating system advisory lock on a
+// file in the configuration directory, which is released by the operating
+// system if the process exits without releasing it. On platforms without
+// advisory file locks the lock only excludes callers within the process.
+// Returns nil, LockTimeoutError if the lock could not be acquired in time.
+func Lock(timeout time.Duration) (*FileLock, error) {
+	path := filepath.Join(ConfigDir(), lockFileName)
+	if err := os.MkdirAll(filepath.Dir(path), 0771); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		select {
+		case lockHeld <- struct{}{}:
+			ok, err := tryLockFile(f)
+			if err != nil {
+				<-lockHeld
+				f.Close()
+				return nil, err
+			}
+			if ok {
+				return &FileLock{f: f}, nil
+			}
+			<-lockHeld
+		default:
+		}
+		if !time.Now().Before(deadline) {
+			f.Close()
+			return nil, &LockTimeoutError{Path: path, Timeout: timeout}
+		}
+		time.Sleep(min(lockRetryInterval, time.Until(deadline)))
+	}
+}
+
+// Unlock releases the lock. The lock file is left in place, because removing
+// it could let another process lock a file that is about to be replaced.
+// Returns os.ErrClosed if the lock was already released.
+func (l *FileLock) Unlock() error {
+	if l.f == nil {
+		return os.ErrClosed
+	}
+	f := l.f
+	l.f = nil
+	err := unlockFile(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	<-lockHeld
+	return err
+}
+
+// Update performs a read-modify-write cycle of the gh configuration files
+// while holding the configuration lock, so that concurrent updates by other
+// processes are not lost. The configuration is read from disk, passed to fn,
+// and written if fn returns nil. The configuration returned by Read is
+// replaced with the updated configuration. The lock is not re-entrant, so fn
+// must not call Write, Update or Lock; they would wait for the lock held by
+// Update and fail with a LockTimeoutError.
+func Update(fn func(*Config) error) error {
+	l, err := Lock(DefaultLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer l.Unlock()
+
+	c, err := load(generalConfigFile(), hostsConfigFile(), nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(c); err != nil {
+		return err
+	}
+	if err := write(c); err != nil {
+		return err
+	}
+	setCached(c)
+	return nil
+}
@@ -0,0 +1,216 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLock(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GH_CONFIG_DIR", dir)
+	lockPath := filepath.Join(dir, "config.lock")
+
+	l, err := Lock(time.Second)
+	assert.NoError(t, err)
+	assert.FileExists(t, lockPath)
+
+	_, err = Lock(10 * time.Millisecond)
+	var timeoutErr *LockTimeoutError
+	assert.ErrorAs(t, err, &timeoutErr)
+	assert.Equal(t, lockPath, timeoutErr.Path)
+	assert.EqualError(t, err, fmt.Sprintf("timed out after 10ms waiting for another gh process to release the configuration lock %s", lockPath))
+
+	assert.NoError(t, l.Unlock())
+	assert.ErrorIs(t, l.Unlock(), os.ErrClosed)
+
+	l, err = Lock(0)
+	assert.NoError(t, err)
+	assert.NoError(t, l.Unlock())
+}
+
+func TestLockLeftBehind(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GH_CONFIG_DIR", dir)
+
+	// A lock file that is not locked by any process does not block.
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "config.lock"), []byte("12345"), 0o600))
+	l, err := Lock(0)
+	assert.NoError(t, err)
+	assert.NoError(t, l.Unlock())
+}
+
+func TestLockHeldByOtherFile(t *testing.T) {
+	if !hasFileLocks() {
+		t.Skip("advisory file locks are not supported")
+	}
+	dir := t.TempDir()
+	t.Setenv("GH_CONFIG_DIR", dir)
+
+	l, err := Lock(time.Second)
+	assert.NoError(t, err)
+
+	// Another process opening the lock file cannot lock it.
+	f, err := os.OpenFile(filepath.Join(dir, "config.lock"), os.O_RDWR, 0)
+	assert.NoError(t, err)
+	defer f.Close()
+	ok, err := tryLockFile(f)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, l.Unlock())
+	ok, err = tryLockFile(f)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.NoError(t, unlockFile(f))
+}
+
+func TestWriteIsAtomic(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GH_CONFIG_DIR", dir)
+	configFile := filepath.Join(dir, "config.yml")
+	assert.NoError(t, os.WriteFile(configFile, []byte("git_protocol: ssh\n"), 0o640))
+
+	cfg := ReadFromString("git_protocol: ssh\n")
+	cfg.Set([]string{"editor"}, "vim")
+	assert.NoError(t, Write(cfg))
+
+	data, err := os.ReadFile(configFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "git_protocol: ssh\neditor: vim\n", string(data))
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(configFile)
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+	}
+
+	// No temporary files are left behind.
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	names := []string{}
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.ElementsMatch(t, []string{"config.lock", "config.yml"}, names)
+}
+
+func TestWriteThroughSymlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symbolic links requires elevated privileges")
+	}
+	dir := t.TempDir()
+	t.Setenv("GH_CONFIG_DIR", dir)
+	target := filepath.Join(t.TempDir(), "dotfiles-config.yml")
+	assert.NoError(t, os.WriteFile(target, []byte("git_protocol: ssh\n"), 0o600))
+	assert.NoError(t, os.Symlink(target, filepath.Join(dir, "config.yml")))
+
+	cfg := ReadFromString("git_protocol: ssh\n")
+	cfg.Set([]string{"git_protocol"}, "https")
+	assert.NoError(t, Write(cfg))
+
+	link, err := os.Readlink(filepath.Join(dir, "config.yml"))
+	assert.NoError(t, err)
+	assert.Equal(t, target, link)
+	data, err := os.ReadFile(target)
+	assert.NoError(t, err)
+	assert.Equal(t, "git_protocol: https\n", string(data))
+}
+
+func TestWriteLocked(t *testing.T) {
+	if !hasFileLocks() {
+		t.Skip("advisory file locks are not supported")
+	}
+	dir := t.TempDir()
+	t.Setenv("GH_CONFIG_DIR", dir)
+
+	// Simulate another process holding the lock.
+	f, err := os.OpenFile(filepath.Join(dir, "config.lock"), os.O_RDWR|os.O_CREATE, 0o600)
+	assert.NoError(t, err)
+	defer f.Close()
+	ok, err := tryLockFile(f)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	done := make(chan error)
+	go func() {
+		cfg := ReadFromString("")
+		cfg.Set([]string{"editor"}, "vim")
+		done <- Write(cfg)
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("write should wait for the lock, got %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.NoError(t, unlockFile(f))
+	assert.NoError(t, <-done)
+}
+
+func TestUpdate(t *testing.T) {
+	dir := stubConfigDir(t)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte("github.com:\n  oauth_token: xxxx\n"), 0o600))
+	c, err := Read(nil)
+	assert.NoError(t, err)
+
+	// Concurrent updates are serialized so that none of them are lost.
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		host := fmt.Sprintf("ghe%d.example.com", i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, Update(func(c *Config) error {
+				c.Set([]string{"hosts", host, "oauth_token"}, "yyyy")
+				return nil
+			}))
+		}()
+	}
+	wg.Wait()
+
+	loaded, err := load(generalConfigFile(), hostsConfigFile(), nil)
+	assert.NoError(t, err)
+	hosts, err := loaded.Keys([]string{"hosts"})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"github.com", "ghe0.example.com", "ghe1.example.com", "ghe2.example.com", "ghe3.example.com", "ghe4.example.com"}, hosts)
+
+	// The configuration returned by Read reflects the updates.
+	assertKeyWithValue(t, c, []string{"hosts", "ghe3.example.com", "oauth_token"}, "yyyy")
+	assertKeyWithValue(t, c, []string{"hosts", "github.com", "oauth_token"}, "xxxx")
+}
+
+func TestUpdateError(t *testing.T) {
+	dir := stubConfigDir(t)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "config.yml"), []byte("editor: vim\n"), 0o600))
+
+	wantErr := errors.New("abort")
+	err := Update(func(c *Config) error {
+		c.Set([]string{"editor"}, "nano")
+		return wantErr
+	})
+	assert.ErrorIs(t, err, wantErr)
+
+	data, err := os.ReadFile(filepath.Join(dir, "config.yml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "editor: vim\n", string(data))
+
+	// The lock is released.
+	l, err := Lock(0)
+	assert.NoError(t, err)
+	assert.NoError(t, l.Unlock())
+}
+
+func hasFileLocks() bool {
+	switch runtime.GOOS {
+	case "darwin", "dragonfly", "freebsd", "linux", "netbsd", "openbsd", "solaris", "illumos", "windows":
+		return true
+	}
+	return false
+}
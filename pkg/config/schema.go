@@ -0,0 +1,282 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OptionType is the type of the value of a configuration option.
+type OptionType int
+
+const (
+	// OptionTypeString is a free-form string.
+	OptionTypeString OptionType = iota
+	// OptionTypeEnum is one of a fixed set of strings.
+	OptionTypeEnum
+	// OptionTypeBool is "enabled" or "disabled".
+	OptionTypeBool
+)
+
+// Option describes a configuration option known to gh.
+type Option struct {
+	Key           string
+	Description   string
+	Type          OptionType
+	AllowedValues []string
+	DefaultValue  string
+}
+
+// Options are the configuration options known to gh. Each option can be set
+// globally, or per host under the "hosts" key.
+var Options = []Option{
+	{
+		Key:           "git_protocol",
+		Description:   "the protocol to use for git clone and push operations",
+		Type:          OptionTypeEnum,
+		AllowedValues: []string{"https", "ssh"},
+		DefaultValue:  "https",
+	},
+	{
+		Key:         "editor",
+		Description: "the text editor program to use for authoring text",
+		Type:        OptionTypeString,
+	},
+	{
+		Key:           "prompt",
+		Description:   "toggle interactive prompting in the terminal",
+		Type:          OptionTypeBool,
+		AllowedValues: []string{"enabled", "disabled"},
+		DefaultValue:  "enabled",
+	},
+	{
+		Key:           "prefer_editor_prompt",
+		Description:   "toggle preference for editor-based interactive prompting in the terminal",
+		Type:          OptionTypeBool,
+		AllowedValues: []string{"enabled", "disabled"},
+		DefaultValue:  "disabled",
+	},
+	{
+		Key:         "pager",
+		Description: "the terminal pager program to send standard output to",
+		Type:        OptionTypeString,
+	},
+	{
+		Key:         "http_unix_socket",
+		Description: "the path to a Unix socket through which to make an HTTP connection",
+		Type:        OptionTypeString,
+	},
+	{
+		Key:         "browser",
+		Description: "the web browser to use for opening URLs",
+		Type:        OptionTypeString,
+	},
+	{
+		Key:           "color_labels",
+		Description:   "whether to display labels using their RGB hex color codes in terminals that support truecolor",
+		Type:          OptionTypeBool,
+		AllowedValues: []string{"enabled", "disabled"},
+		DefaultValue:  "disabled",
+	},
+}
+
+// LookupOption returns the known option with the given key.
+func LookupOption(key string) (Option, bool) {
+	for _, o := range Options {
+		if o.Key == key {
+			return o, true
+		}
+	}
+	return Option{}, false
+}
+
+// InvalidValueError represents an error when a config value
+// is not valid for its option.
+type InvalidValueError struct {
+	Key           string
+	Value         string
+	AllowedValues []string
+	Err           error
+}
+
+// Allow InvalidValueError to satisfy error interface.
+func (e *InvalidValueError) Error() string {
+	if len(e.AllowedValues) > 0 {
+		return fmt.Sprintf("invalid value %q for key %q, valid values: %s", e.Value, e.Key, strings.Join(e.AllowedValues, ", "))
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("invalid value %q for key %q: %s", e.Value, e.Key, e.Err)
+	}
+	return fmt.Sprintf("invalid value %q for key %q", e.Value, e.Key)
+}
+
+// Allow InvalidValueError to be unwrapped.
+func (e *InvalidValueError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateValue returns an InvalidValueError if value is not valid
+// for the known option key. Values of unknown keys are always valid.
+func ValidateValue(key, value string) error {
+	o, ok := LookupOption(key)
+	if !ok {
+		return nil
+	}
+	switch o.Type {
+	case OptionTypeEnum, OptionTypeBool:
+		_, err := parseEnum(key, value, o.AllowedValues)
+		return err
+	}
+	return nil
+}
+
+// Source is the layer of the configuration that supplied a value.
+type Source int
+
+const (
+	// SourceHost is a value set for a host under the "hosts" key.
+	SourceHost Source = iota + 1
+	// SourceGlobal is a value set at the top level of the configuration.
+	SourceGlobal
+	// SourceDefault is the default value of a known option.
+	SourceDefault
+)
+
+// String returns the name of the source.
+func (s Source) String() string {
+	switch s {
+	case SourceHost:
+		return "host"
+	case SourceGlobal:
+		return "global"
+	case SourceDefault:
+		return "default"
+	}
+	return "unknown"
+}
+
+// Entry is a value resolved by Lookup along with its source.
+type Entry struct {
+	Value  string
+	Source Source
+}
+
+// Lookup resolves the value of key for host the same way gh does. The value
+// set for the host takes precedence over the global value, which takes
+// precedence over the default value of the known option. If host is empty
+// only the global value and the default are considered.
+// Returns Entry{}, KeyNotFoundError if the key is not set and has no default.
+func (c *Config) Lookup(host, key string) (Entry, error) {
+	if host != "" {
+		if value, err := c.Get([]string{"hosts", host, key}); err == nil {
+			return Entry{Value: value, Source: SourceHost}, nil
+		}
+	}
+	if value, err := c.Get([]string{key}); err == nil {
+		return Entry{Value: value, Source: SourceGlobal}, nil
+	}
+	if o, ok := LookupOption(key); ok && o.DefaultValue != "" {
+		return Entry{Value: o.DefaultValue, Source: SourceDefault}, nil
+	}
+	return Entry{}, &KeyNotFoundError{key}
+}
+
+// GetOrDefault returns the value of key for host.
+// See Lookup for how the value is resolved.
+// Returns "", KeyNotFoundError if the key is not set and has no default.
+func (c *Config) GetOrDefault(host, key string) (string, error) {
+	entry, err := c.Lookup(host, key)
+	return entry.Value, err
+}
+
+// GetBool returns the value of key for host as a bool. Boolean options known
+// to gh are read the way gh reads them: only the value opposite to the default
+// changes the result, so "prompt: false" leaves prompting enabled. Other keys
+// may be "enabled" or "disabled", or a value accepted by strconv.ParseBool.
+// See GetOrDefault for how the value is looked up.
+func (c *Config) GetBool(host, key string) (bool, error) {
+	value, err := c.GetOrDefault(host, key)
+	if err != nil {
+		return false, err
+	}
+	if o, ok := LookupOption(key); ok && o.Type == OptionTypeBool {
+		if o.DefaultValue == "enabled" {
+			return value != "disabled", nil
+		}
+		return value == "enabled", nil
+	}
+	return parseBool(key, value)
+}
+
+// GetEnum returns the value of key for host, which must be one of the allowed
+// values of the known option. See GetOrDefault for how the value is looked up.
+func (c *Config) GetEnum(host, key string) (string, error) {
+	value, err := c.GetOrDefault(host, key)
+	if err != nil {
+		return "", err
+	}
+	o, _ := LookupOption(key)
+	return parseEnum(key, value, o.AllowedValues)
+}
+
+// GetDuration returns the value of key for host as a time.Duration.
+// See GetOrDefault for how the value is looked up.
+func (c *Config) GetDuration(host, key string) (time.Duration, error) {
+	value, err := c.GetOrDefault(host, key)
+	if err != nil {
+		return 0, err
+	}
+	return parseDuration(key, value)
+}
+
+// GetList returns the value of key for host as a list of strings. The value
+// is split on commas and surrounding whitespace is removed from each item.
+// See GetOrDefault for how the value is looked up.
+func (c *Config) GetList(host, key string) ([]string, error) {
+	value, err := c.GetOrDefault(host, key)
+	if err != nil {
+		return nil, err
+	}
+	list := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list, nil
+}
+
+func parseBool(key, value string) (bool, error) {
+	switch value {
+	case "enabled":
+		return true, nil
+	case "disabled":
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, &InvalidValueError{Key: key, Value: value, AllowedValues: []string{"enabled", "disabled"}}
+	}
+	return b, nil
+}
+
+func parseEnum(key, value string, allowed []string) (string, error) {
+	if len(allowed) == 0 {
+		return value, nil
+	}
+	for _, a := range allowed {
+		if value == a {
+			return value, nil
+		}
+	}
+	return "", &InvalidValueError{Key: key, Value: value, AllowedValues: allowed}
+}
+
+func parseDuration(key, value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, &InvalidValueError{Key: key, Value: value, Err: err}
+	}
+	return d, nil
+}
@@ -0,0 +1,256 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testSchemaConfig() *Config {
+	return ReadFromString(`
+git_protocol: ssh
+editor: vim
+prompt: disabled
+check_interval: 90m
+topics: cli, go , ,api
+hosts:
+  github.com:
+    git_protocol: https
+    prompt: enabled
+  example.com:
+    git_protocol: smoke-signals
+    check_interval: soon
+`)
+}
+
+func TestGetOrDefault(t *testing.T) {
+	tests := []struct {
+		name      string
+		host      string
+		key       string
+		wantValue string
+		wantErr   string
+	}{
+		{
+			name:      "host value",
+			host:      "github.com",
+			key:       "git_protocol",
+			wantValue: "https",
+		},
+		{
+			name:      "global value for unknown host",
+			host:      "ghe.io",
+			key:       "git_protocol",
+			wantValue: "ssh",
+		},
+		{
+			name:      "global value without host",
+			key:       "editor",
+			wantValue: "vim",
+		},
+		{
+			name:      "default value",
+			host:      "github.com",
+			key:       "color_labels",
+			wantValue: "disabled",
+		},
+		{
+			name:    "known key without default",
+			key:     "pager",
+			wantErr: `could not find key "pager"`,
+		},
+		{
+			name:    "unknown key",
+			key:     "unknown",
+			wantErr: `could not find key "unknown"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := testSchemaConfig().GetOrDefault(tt.host, tt.key)
+			if tt.wantErr != "" {
+				var keyErr *KeyNotFoundError
+				assert.ErrorAs(t, err, &keyErr)
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantValue, value)
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name      string
+		host      string
+		key       string
+		wantEntry Entry
+		wantErr   bool
+	}{
+		{
+			name:      "host layer",
+			host:      "github.com",
+			key:       "git_protocol",
+			wantEntry: Entry{Value: "https", Source: SourceHost},
+		},
+		{
+			name:      "global layer for host without override",
+			host:      "github.com",
+			key:       "editor",
+			wantEntry: Entry{Value: "vim", Source: SourceGlobal},
+		},
+		{
+			name:      "global layer without host",
+			key:       "git_protocol",
+			wantEntry: Entry{Value: "ssh", Source: SourceGlobal},
+		},
+		{
+			name:      "default layer",
+			host:      "example.com",
+			key:       "prefer_editor_prompt",
+			wantEntry: Entry{Value: "disabled", Source: SourceDefault},
+		},
+		{
+			name:    "not found",
+			host:    "example.com",
+			key:     "browser",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := testSchemaConfig().Lookup(tt.host, tt.key)
+			if tt.wantErr {
+				assert.EqualError(t, err, `could not find key "`+tt.key+`"`)
+				assert.Equal(t, Entry{}, entry)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantEntry, entry)
+		})
+	}
+}
+
+func TestLookupEmptyHostValue(t *testing.T) {
+	// An empty value set for a host overrides the global value, as it does in gh.
+	cfg := testSchemaConfig()
+	cfg.Set([]string{"hosts", "github.com", "editor"}, "")
+
+	entry, err := cfg.Lookup("github.com", "editor")
+	assert.NoError(t, err)
+	assert.Equal(t, Entry{Value: "", Source: SourceHost}, entry)
+}
+
+func TestSourceString(t *testing.T) {
+	assert.Equal(t, "host", SourceHost.String())
+	assert.Equal(t, "global", SourceGlobal.String())
+	assert.Equal(t, "default", SourceDefault.String())
+	assert.Equal(t, "unknown", Source(0).String())
+}
+
+func TestGetBool(t *testing.T) {
+	cfg := testSchemaConfig()
+
+	b, err := cfg.GetBool("", "prompt")
+	assert.NoError(t, err)
+	assert.False(t, b)
+
+	b, err = cfg.GetBool("github.com", "prompt")
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	b, err = cfg.GetBool("", "prefer_editor_prompt")
+	assert.NoError(t, err)
+	assert.False(t, b)
+
+	// Only the value opposite to the default changes the result, as in gh.
+	cfg.Set([]string{"prompt"}, "false")
+	b, err = cfg.GetBool("", "prompt")
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	cfg.Set([]string{"prefer_editor_prompt"}, "true")
+	b, err = cfg.GetBool("", "prefer_editor_prompt")
+	assert.NoError(t, err)
+	assert.False(t, b)
+
+	cfg.Set([]string{"prefer_editor_prompt"}, "enabled")
+	b, err = cfg.GetBool("", "prefer_editor_prompt")
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	cfg.Set([]string{"verbose"}, "true")
+	b, err = cfg.GetBool("", "verbose")
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	_, err = cfg.GetBool("", "editor")
+	assert.EqualError(t, err, `invalid value "vim" for key "editor", valid values: enabled, disabled`)
+}
+
+func TestGetEnum(t *testing.T) {
+	cfg := testSchemaConfig()
+
+	value, err := cfg.GetEnum("github.com", "git_protocol")
+	assert.NoError(t, err)
+	assert.Equal(t, "https", value)
+
+	_, err = cfg.GetEnum("example.com", "git_protocol")
+	var invalidErr *InvalidValueError
+	assert.ErrorAs(t, err, &invalidErr)
+	assert.Equal(t, "smoke-signals", invalidErr.Value)
+	assert.EqualError(t, err, `invalid value "smoke-signals" for key "git_protocol", valid values: https, ssh`)
+}
+
+func TestGetDuration(t *testing.T) {
+	cfg := testSchemaConfig()
+
+	d, err := cfg.GetDuration("github.com", "check_interval")
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, d)
+
+	_, err = cfg.GetDuration("example.com", "check_interval")
+	assert.EqualError(t, err, `invalid value "soon" for key "check_interval": time: invalid duration "soon"`)
+}
+
+func TestGetList(t *testing.T) {
+	cfg := testSchemaConfig()
+
+	list, err := cfg.GetList("", "topics")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cli", "go", "api"}, list)
+
+	_, err = cfg.GetList("", "unknown")
+	assert.EqualError(t, err, `could not find key "unknown"`)
+}
+
+func TestValidateValue(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		wantErr string
+	}{
+		{key: "git_protocol", value: "ssh"},
+		{key: "git_protocol", value: "ftp", wantErr: `invalid value "ftp" for key "git_protocol", valid values: https, ssh`},
+		{key: "prompt", value: "disabled"},
+		{key: "prompt", value: "true", wantErr: `invalid value "true" for key "prompt", valid values: enabled, disabled`},
+		{key: "prompt", value: "sometimes", wantErr: `invalid value "sometimes" for key "prompt", valid values: enabled, disabled`},
+		{key: "editor", value: "anything"},
+		{key: "unknown", value: "anything"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			err := ValidateValue(tt.key, tt.value)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultWatchInterval = 2 * time.Second
+
+// WatchOptions holds options for watching the configuration files.
+type WatchOptions struct {
+	// Interval is how often the configuration files are checked for changes.
+	// Default is 2 seconds.
+	Interval time.Duration
+
+	// Fallback is the configuration used when the configuration files are
+	// missing or empty, as it is by Reload.
+	Fallback *Config
+
+	// OnError is called when the configuration files changed but could not
+	// be reloaded, such as when a file contains invalid YAML. Subscribers are
+	// not notified and reloading is attempted again when the files next change.
+	OnError func(error)
+}
+
+// Watcher reloads the configuration when the configuration files change
+// and notifies its subscribers.
+type Watcher struct {
+	opts  WatchOptions
+	paths []string
+
+	mu          sync.Mutex
+	nextID      int
+	subscribers map[int]func(*Config)
+	done        chan struct{}
+}
+
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// Watch starts watching config.yml and hosts.yml for changes by polling their
+// modification times, which works on every platform. When a change is detected
+// the configuration is reloaded with Reload and subscribers are notified.
+// Watching stops when ctx is done.
+func Watch(ctx context.Context, opts WatchOptions) *Watcher {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultWatchInterval
+	}
+	w := &Watcher{
+		opts:        opts,
+		paths:       []string{generalConfigFile(), hostsConfigFile()},
+		subscribers: map[int]func(*Config){},
+		done:        make(chan struct{}),
+	}
+	go w.run(ctx, w.stat())
+	return w
+}
+
+// Subscribe registers fn to be called with the reloaded configuration after
+// each change. It returns a function that unregisters fn.
+func (w *Watcher) Subscribe(fn func(*Config)) (unsubscribe func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	id := w.nextID
+	w.nextID++
+	w.subscribers[id] = fn
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.subscribers, id)
+	}
+}
+
+// Done returns a channel that is closed when the watcher has stopped.
+func (w *Watcher) Done() <-chan struct{} {
+	return w.done
+}
+
+func (w *Watcher) run(ctx context.Context, last []fileState) {
+	defer close(w.done)
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current := w.stat()
+		if equalFileStates(last, current) {
+			continue
+		}
+		last = current
+		c, err := Reload(w.opts.Fallback)
+		if err != nil {
+			if w.opts.OnError != nil {
+				w.opts.OnError(err)
+			}
+			continue
+		}
+		w.notify(c)
+	}
+}
+
+func (w *Watcher) notify(c *Config) {
+	w.mu.Lock()
+	subscribers := make([]func(*Config), 0, len(w.subscribers))
+	for i := 0; i < w.nextID; i++ {
+		if fn, ok := w.subscribers[i]; ok {
+			subscribers = append(subscribers, fn)
+		}
+	}
+	w.mu.Unlock()
+	for _, fn := range subscribers {
+		fn(c)
+	}
+}
+
+func (w *Watcher) stat() []fileState {
+	states := make([]fileState, len(w.paths))
+	for i, path := range w.paths {
+		if info, err := os.Stat(path); err == nil {
+			states[i] = fileState{exists: true, modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return states
+}
+
+func equalFileStates(a, b []fileState) bool {
+	for i := range a {
+		if a[i].exists != b[i].exists || a[i].size != b[i].size || !a[i].modTime.Equal(b[i].modTime) {
+			return false
+		}
+	}
+	return true
+}
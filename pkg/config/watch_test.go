@@ -0,0 +1,154 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReload(t *testing.T) {
+	dir := stubConfigDir(t)
+	configFile := filepath.Join(dir, "config.yml")
+	assert.NoError(t, os.WriteFile(configFile, []byte("git_protocol: ssh\n"), 0o600))
+
+	c, err := Read(nil)
+	assert.NoError(t, err)
+	assertKeyWithValue(t, c, []string{"git_protocol"}, "ssh")
+
+	assert.NoError(t, os.WriteFile(configFile, []byte("git_protocol: https\n"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte("github.com:\n  user: monalisa\n"), 0o600))
+	reloaded, err := Reload(nil)
+	assert.NoError(t, err)
+
+	// Existing references observe the reloaded configuration.
+	assert.Same(t, c, reloaded)
+	assertKeyWithValue(t, c, []string{"git_protocol"}, "https")
+	assertKeyWithValue(t, c, []string{"hosts", "github.com", "user"}, "monalisa")
+
+	again, err := Read(nil)
+	assert.NoError(t, err)
+	assert.Same(t, c, again)
+
+	// A failed reload keeps the previous configuration.
+	assert.NoError(t, os.WriteFile(configFile, []byte("invalid"), 0o600))
+	_, err = Reload(nil)
+	var invalidErr *InvalidConfigFileError
+	assert.ErrorAs(t, err, &invalidErr)
+	assertKeyWithValue(t, c, []string{"git_protocol"}, "https")
+}
+
+func TestReloadBeforeRead(t *testing.T) {
+	dir := stubConfigDir(t)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "config.yml"), []byte("editor: vim\n"), 0o600))
+
+	c, err := Reload(nil)
+	assert.NoError(t, err)
+	read, err := Read(nil)
+	assert.NoError(t, err)
+	assert.Same(t, c, read)
+	assertKeyWithValue(t, read, []string{"editor"}, "vim")
+}
+
+func TestWatch(t *testing.T) {
+	dir := stubConfigDir(t)
+	configFile := filepath.Join(dir, "config.yml")
+	assert.NoError(t, os.WriteFile(configFile, []byte("git_protocol: ssh\n"), 0o600))
+	_, err := Read(nil)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var mu sync.Mutex
+	var errs []error
+	w := Watch(ctx, WatchOptions{
+		Interval: time.Millisecond,
+		OnError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		},
+	})
+	changes := make(chan string, 10)
+	w.Subscribe(func(c *Config) {
+		value, _ := c.Get([]string{"git_protocol"})
+		changes <- value
+	})
+	unsubscribe := w.Subscribe(func(c *Config) {
+		t.Error("unsubscribed function should not be called")
+	})
+	unsubscribe()
+
+	assert.NoError(t, os.WriteFile(configFile, []byte("git_protocol: https\n"), 0o600))
+	assert.Equal(t, "https", receive(t, changes))
+
+	// Invalid files are reported without notifying subscribers.
+	assert.NoError(t, os.WriteFile(configFile, []byte("invalid"), 0o600))
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(errs) == 1
+	}, time.Second, time.Millisecond)
+
+	assert.NoError(t, os.Remove(configFile))
+	assert.Equal(t, "", receive(t, changes))
+
+	cancel()
+	select {
+	case <-w.Done():
+	case <-time.After(time.Second):
+		t.Fatal("watcher did not stop")
+	}
+}
+
+func TestWatchFallback(t *testing.T) {
+	dir := stubConfigDir(t)
+	configFile := filepath.Join(dir, "config.yml")
+	assert.NoError(t, os.WriteFile(configFile, []byte("git_protocol: ssh\n"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := Watch(ctx, WatchOptions{
+		Interval: time.Millisecond,
+		Fallback: ReadFromString("git_protocol: https\n"),
+	})
+	changes := make(chan string, 10)
+	w.Subscribe(func(c *Config) {
+		value, _ := c.Get([]string{"git_protocol"})
+		changes <- value
+	})
+
+	assert.NoError(t, os.Remove(configFile))
+	assert.Equal(t, "https", receive(t, changes))
+}
+
+func receive(t *testing.T, ch <-chan string) string {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for change")
+		return ""
+	}
+}
+
+// stubConfigDir points the configuration at an empty temporary directory
+// and resets the configuration cached by Read.
+func stubConfigDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("GH_CONFIG_DIR", dir)
+	resetRead := func() {
+		cfgMu.Lock()
+		defer cfgMu.Unlock()
+		cfg, loadErr, once = nil, nil, sync.Once{}
+	}
+	resetRead()
+	t.Cleanup(resetRead)
+	return dir
+}
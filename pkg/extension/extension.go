@@ -0,0 +1,109 @@
+// Package extension provides the runtime context of a gh extension: the
+// host and repository it should operate on, the terminal it runs in, the
+// gh configuration, and directories where it can keep its own files.
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	gh "github.com/cli/go-gh/v2"
+	"github.com/cli/go-gh/v2/pkg/auth"
+	"github.com/cli/go-gh/v2/pkg/config"
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/cli/go-gh/v2/pkg/term"
+)
+
+const namePrefix = "gh-"
+
+// Options holds options for determining the context of an extension.
+type Options struct {
+	// Name is the name of the extension, with or without the "gh-" prefix.
+	// Default is the name of the running executable.
+	Name string
+}
+
+// Context is the runtime context of an extension.
+type Context struct {
+	// Name is the name of the extension without the "gh-" prefix,
+	// such as "copilot" for the "gh-copilot" extension.
+	Name string
+
+	// Host is the GitHub host to operate on, and HostSource is where it was
+	// determined from. See auth.DefaultHost.
+	Host       string
+	HostSource string
+
+	// Term is the terminal the extension runs in.
+	Term term.Term
+
+	// Config is the gh configuration.
+	Config *config.Config
+
+	// GhPath is the path of the gh executable,
+	// or an empty string if it could not be found.
+	GhPath string
+
+	// DataDir is where the extension can keep data that should persist,
+	// and StateDir is where it can keep state such as history or caches
+	// that can be regenerated. Neither directory is created.
+	DataDir  string
+	StateDir string
+
+	repo    repository.Repository
+	repoErr error
+}
+
+// Current returns the context of the running extension.
+func Current() (*Context, error) {
+	return New(Options{})
+}
+
+// New returns the context of the running extension configured by opts.
+func New(opts Options) (*Context, error) {
+	cfg, err := config.Read(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = nameFromExecutable(os.Args[0])
+	}
+	name = strings.TrimPrefix(name, namePrefix)
+
+	host, hostSource := auth.DefaultHost()
+	ghPath, _ := gh.Path()
+	repo, repoErr := repository.Current()
+
+	return &Context{
+		Name:       name,
+		Host:       host,
+		HostSource: hostSource,
+		Term:       term.FromEnv(),
+		Config:     cfg,
+		GhPath:     ghPath,
+		DataDir:    filepath.Join(config.DataDir(), "extension-data", namePrefix+name),
+		StateDir:   filepath.Join(config.StateDir(), "extensions", namePrefix+name),
+		repo:       repo,
+		repoErr:    repoErr,
+	}, nil
+}
+
+// Repository returns the repository of the current directory, as determined
+// by the GH_REPO environment variable or the git remotes of the directory.
+// An error is returned if the extension is not running in a repository.
+func (c *Context) Repository() (repository.Repository, error) {
+	return c.repo, c.repoErr
+}
+
+// nameFromExecutable returns the name of an extension from the path it was invoked with.
+func nameFromExecutable(path string) string {
+	name := filepath.Base(path)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, ".exe")
+	}
+	return name
+}
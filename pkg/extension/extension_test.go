@@ -0,0 +1,83 @@
+package extension
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/config"
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tempDir, "data"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(tempDir, "state"))
+	t.Setenv("GH_PATH", "/usr/local/bin/gh")
+	t.Setenv("GH_HOST", "")
+	t.Setenv("GH_REPO", "octo-org/octo-repo")
+	stubConfig(t, "hosts:\n  github.example.com:\n    user: monalisa\n")
+
+	tests := []struct {
+		name     string
+		opts     Options
+		wantName string
+	}{
+		{
+			name:     "name",
+			opts:     Options{Name: "triage"},
+			wantName: "triage",
+		},
+		{
+			name:     "name with prefix",
+			opts:     Options{Name: "gh-triage"},
+			wantName: "triage",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := New(tt.opts)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantName, ctx.Name)
+			assert.Equal(t, "github.example.com", ctx.Host)
+			assert.Equal(t, "hosts", ctx.HostSource)
+			assert.Equal(t, "/usr/local/bin/gh", ctx.GhPath)
+			assert.Equal(t, filepath.Join(tempDir, "data", "gh", "extension-data", "gh-triage"), ctx.DataDir)
+			assert.Equal(t, filepath.Join(tempDir, "state", "gh", "extensions", "gh-triage"), ctx.StateDir)
+
+			user, err := ctx.Config.Get([]string{"hosts", "github.example.com", "user"})
+			assert.NoError(t, err)
+			assert.Equal(t, "monalisa", user)
+
+			repo, err := ctx.Repository()
+			assert.NoError(t, err)
+			assert.Equal(t, repository.Repository{Host: "github.example.com", Owner: "octo-org", Name: "octo-repo"}, repo)
+		})
+	}
+}
+
+func TestNewRepositoryError(t *testing.T) {
+	t.Setenv("GH_REPO", "not-a-repo")
+	stubConfig(t, "")
+
+	ctx, err := New(Options{Name: "triage"})
+	assert.NoError(t, err)
+	_, err = ctx.Repository()
+	assert.EqualError(t, err, `expected the "[HOST/]OWNER/REPO" format, got "not-a-repo"`)
+}
+
+func TestNameFromExecutable(t *testing.T) {
+	assert.Equal(t, "gh-triage", nameFromExecutable(filepath.Join("home", "monalisa", "gh-triage")))
+}
+
+func stubConfig(t *testing.T, cfgStr string) {
+	t.Helper()
+	old := config.Read
+	config.Read = func(_ *config.Config) (*config.Config, error) {
+		return config.ReadFromString(cfgStr), nil
+	}
+	t.Cleanup(func() {
+		config.Read = old
+	})
+}
@@ -0,0 +1,197 @@
+// Package graphqlschema fetches GitHub GraphQL API schemas via introspection
+// and validates queries against them without sending them to the API.
+package graphqlschema
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/internal/graphql"
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
+	"github.com/cli/go-gh/v2/pkg/config"
+)
+
+const defaultCacheTTL = 24 * time.Hour
+
+// Schema is a GraphQL schema that queries can be validated against.
+type Schema struct {
+	schema *graphql.Schema
+}
+
+// FetchOptions holds the options for fetching a schema.
+type FetchOptions struct {
+	// Host is the host the client sends requests to.
+	// It is used to key the cached schema.
+	// Default is the host returned by auth.DefaultHost.
+	Host string
+
+	// CacheDir is the directory the introspection result is cached in.
+	// Default is the directory returned by config.CacheDir.
+	CacheDir string
+
+	// CacheTTL is how long the cached schema is used before it is fetched again.
+	// A negative value disables the cache.
+	// Default is 24 hours.
+	CacheTTL time.Duration
+}
+
+// ValidationError represents the problems found when validating a query
+// against a schema.
+type ValidationError struct {
+	Errors []ValidationErrorItem
+	query  string
+}
+
+// ValidationErrorItem stores the message and location of a single problem
+// found when validating a query.
+type ValidationErrorItem struct {
+	Message string
+	Line    int
+	Column  int
+}
+
+// Allow ValidationError to satisfy error interface.
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, item := range e.Errors {
+		messages = append(messages, formatError("failed to validate GraphQL query", e.query, item.Line, item.Column, item.Message))
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Fetch retrieves the schema of the GraphQL API using an introspection query.
+// The introspection result is cached on disk so that subsequent calls do not
+// need to query the API again until the cache expires.
+func Fetch(ctx context.Context, client *api.GraphQLClient, opts FetchOptions) (*Schema, error) {
+	if opts.Host == "" {
+		opts.Host, _ = auth.DefaultHost()
+	}
+	if opts.CacheDir == "" {
+		opts.CacheDir = config.CacheDir()
+	}
+	if opts.CacheTTL == 0 {
+		opts.CacheTTL = defaultCacheTTL
+	}
+
+	cacheFile := filepath.Join(opts.CacheDir, "graphql-schema", fmt.Sprintf("%x.json", sha256.Sum256([]byte(opts.Host))))
+	if opts.CacheTTL > 0 {
+		if data, ok := readCache(cacheFile, opts.CacheTTL); ok {
+			if schema, err := ParseIntrospection(data); err == nil {
+				return schema, nil
+			}
+		}
+	}
+
+	var data json.RawMessage
+	if err := client.DoWithContext(ctx, graphql.IntrospectionQuery, nil, &data); err != nil {
+		return nil, err
+	}
+	schema, err := ParseIntrospection(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.CacheTTL > 0 {
+		// Failing to cache the schema is not fatal since it was fetched successfully.
+		_ = writeCache(cacheFile, data)
+	}
+	return schema, nil
+}
+
+// Parse parses a schema written in the GraphQL schema definition language.
+func Parse(sdl string) (*Schema, error) {
+	schema, err := graphql.ParseSchema("", sdl)
+	if err != nil {
+		var e *graphql.Error
+		if errors.As(err, &e) {
+			return nil, errors.New(formatError("failed to parse GraphQL schema", sdl, e.Pos.Line, e.Pos.Column, e.Message))
+		}
+		return nil, err
+	}
+	return &Schema{schema: schema}, nil
+}
+
+// ParseIntrospection parses the JSON result of an introspection query.
+// The result may be either the full response or only its data.
+func ParseIntrospection(data []byte) (*Schema, error) {
+	schema, err := graphql.ParseIntrospection(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GraphQL introspection result: %w", err)
+	}
+	return &Schema{schema: schema}, nil
+}
+
+// Validate checks a query against the schema before it is sent to the API.
+// The fields, arguments, fragments, and variable usages of the query are
+// validated, as are the variables provided for it. Variables of non-null
+// types without defaults must be provided. A nil variables map skips
+// validation of the variable values.
+// Syntax errors are returned as an error formatted with the line and column
+// of the problem, and other problems are returned as a *ValidationError.
+func (s *Schema) Validate(query string, variables map[string]interface{}) error {
+	doc, err := graphql.ParseQuery("", query)
+	if err != nil {
+		var e *graphql.Error
+		if errors.As(err, &e) {
+			return errors.New(formatError("failed to parse GraphQL query", query, e.Pos.Line, e.Pos.Column, e.Message))
+		}
+		return err
+	}
+
+	errs := graphql.Validate(s.schema, doc)
+	if len(errs) == 0 && variables != nil {
+		errs, err = validateVariables(s.schema, doc, variables)
+		if err != nil {
+			return err
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+
+	verr := &ValidationError{query: query}
+	for _, e := range errs {
+		verr.Errors = append(verr.Errors, ValidationErrorItem{
+			Message: e.Message,
+			Line:    e.Pos.Line,
+			Column:  e.Pos.Column,
+		})
+	}
+	return verr
+}
+
+func readCache(filename string, ttl time.Duration) ([]byte, bool) {
+	info, err := os.Stat(filename)
+	if err != nil || time.Since(info.ModTime()) > ttl {
+		return nil, false
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+func writeCache(filename string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0600)
+}
+
+func formatError(prefix, src string, line, column int, message string) string {
+	lines := strings.Split(src, "\n")
+	str := ""
+	if line > 0 && line <= len(lines) {
+		str = strings.TrimSuffix(lines[line-1], "\r")
+	}
+	return fmt.Sprintf("%s (line %d, column %d)\n    %s\n    %*c  %s", prefix, line, column, str, column, '^', message)
+}
@@ -0,0 +1,227 @@
+package graphqlschema
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/api/apitest"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSchema = `
+type Query {
+  repository(owner: String!, name: String!): Repository
+  viewer: User!
+}
+
+type Mutation {
+  addStar(input: AddStarInput!): AddStarPayload
+}
+
+type Repository {
+  name: String!
+  issues(first: Int, states: [IssueState!]): IssueConnection!
+}
+
+type User {
+  login: String!
+}
+
+type IssueConnection {
+  totalCount: Int!
+}
+
+enum IssueState {
+  OPEN
+  CLOSED
+}
+
+input AddStarInput {
+  starrableId: ID!
+  clientMutationId: String
+}
+
+type AddStarPayload {
+  clientMutationId: String
+}
+`
+
+const testIntrospection = `{
+  "__schema": {
+    "queryType": {"name": "Query"},
+    "mutationType": null,
+    "subscriptionType": null,
+    "types": [
+      {
+        "kind": "OBJECT",
+        "name": "Query",
+        "fields": [
+          {"name": "viewer", "args": [], "type": {"kind": "NON_NULL", "ofType": {"kind": "OBJECT", "name": "User"}}}
+        ]
+      },
+      {
+        "kind": "OBJECT",
+        "name": "User",
+        "fields": [
+          {"name": "login", "args": [], "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "String"}}}
+        ]
+      }
+    ],
+    "directives": []
+  }
+}`
+
+func TestFetch(t *testing.T) {
+	s := apitest.NewServer("token")
+	s.HandleGraphQL("IntrospectionQuery", func(variables map[string]interface{}) (interface{}, error) {
+		return json.RawMessage(testIntrospection), nil
+	})
+	client, err := api.NewGraphQLClient(s.ClientOptions())
+	assert.NoError(t, err)
+
+	cacheDir := t.TempDir()
+	opts := FetchOptions{Host: "github.com", CacheDir: cacheDir}
+	schema, err := Fetch(context.Background(), client, opts)
+	assert.NoError(t, err)
+	assert.NoError(t, schema.Validate(`{ viewer { login } }`, nil))
+	assert.Len(t, s.Calls(), 1)
+
+	matches, _ := filepath.Glob(filepath.Join(cacheDir, "graphql-schema", "*.json"))
+	assert.Len(t, matches, 1)
+
+	// The second fetch is served from the cache.
+	schema, err = Fetch(context.Background(), client, opts)
+	assert.NoError(t, err)
+	assert.NoError(t, schema.Validate(`{ viewer { login } }`, nil))
+	assert.Len(t, s.Calls(), 1)
+
+	// Expired cache entries are fetched again.
+	expired := time.Now().Add(-48 * time.Hour)
+	assert.NoError(t, os.Chtimes(matches[0], expired, expired))
+	_, err = Fetch(context.Background(), client, opts)
+	assert.NoError(t, err)
+	assert.Len(t, s.Calls(), 2)
+
+	// Other hosts do not share the cache.
+	_, err = Fetch(context.Background(), client, FetchOptions{Host: "example.com", CacheDir: cacheDir})
+	assert.NoError(t, err)
+	assert.Len(t, s.Calls(), 3)
+
+	// A negative TTL disables the cache.
+	noCacheDir := t.TempDir()
+	_, err = Fetch(context.Background(), client, FetchOptions{Host: "github.com", CacheDir: noCacheDir, CacheTTL: -1})
+	assert.NoError(t, err)
+	assert.Len(t, s.Calls(), 4)
+	entries, _ := os.ReadDir(noCacheDir)
+	assert.Empty(t, entries)
+}
+
+func TestFetchError(t *testing.T) {
+	s := apitest.NewServer("token")
+	client, err := api.NewGraphQLClient(s.ClientOptions())
+	assert.NoError(t, err)
+
+	_, err = Fetch(context.Background(), client, FetchOptions{Host: "github.com", CacheDir: t.TempDir()})
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	schema, err := Parse(testSchema)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		wantErr   string
+	}{
+		{
+			name:      "valid query",
+			query:     `query Q($owner: String!, $states: [IssueState!]) { repository(owner: $owner, name: "cli") { issues(first: 1, states: $states) { totalCount } } }`,
+			variables: map[string]interface{}{"owner": "cli", "states": []string{"OPEN"}},
+		},
+		{
+			name:  "valid query without checking variables",
+			query: `query Q($owner: String!) { repository(owner: $owner, name: "cli") { name } }`,
+		},
+		{
+			name:  "introspection query",
+			query: `query { __type(name: "Repository") { name fields { name } } __schema { queryType { name } } }`,
+		},
+		{
+			name:    "syntax error",
+			query:   "query {\n  viewer {\n    login\n  ]\n}",
+			wantErr: "failed to parse GraphQL query (line 4, column 3)\n      ]\n      ^  expected name, found \"]\"",
+		},
+		{
+			name:  "unknown field",
+			query: "{\n  viewer { logn }\n}",
+			wantErr: "failed to validate GraphQL query (line 2, column 12)\n      viewer { logn }\n" +
+				"               ^  field \"logn\" does not exist on type \"User\", did you mean \"login\"?",
+		},
+		{
+			name:  "argument type mismatch",
+			query: `{ repository(owner: "cli", name: 1) { name } }`,
+			wantErr: "failed to validate GraphQL query (line 1, column 34)\n    { repository(owner: \"cli\", name: 1) { name } }\n" +
+				"                                     ^  expected value of type \"String!\", found 1",
+		},
+		{
+			name:      "missing required variable",
+			query:     `query Q($owner: String!) { repository(owner: $owner, name: "cli") { name } }`,
+			variables: map[string]interface{}{},
+			wantErr: "failed to validate GraphQL query (line 1, column 9)\n    query Q($owner: String!) { repository(owner: $owner, name: \"cli\") { name } }\n" +
+				"            ^  variable $owner of required type \"String!\" was not provided",
+		},
+		{
+			name:      "invalid variable value",
+			query:     `query Q($states: [IssueState!]) { repository(owner: "cli", name: "cli") { issues(states: $states) { totalCount } } }`,
+			variables: map[string]interface{}{"states": []string{"OPEN", "MERGED"}},
+			wantErr: "failed to validate GraphQL query (line 1, column 9)\n    query Q($states: [IssueState!]) { repository(owner: \"cli\", name: \"cli\") { issues(states: $states) { totalCount } } }\n" +
+				"            ^  variable $states got invalid value \"MERGED\" at \"[1]\"; expected type \"IssueState!\"",
+		},
+		{
+			name:      "invalid input object variable",
+			query:     `mutation M($input: AddStarInput!) { addStar(input: $input) { clientMutationId } }`,
+			variables: map[string]interface{}{"input": map[string]interface{}{"clientMutationId": "x"}},
+			wantErr: "failed to validate GraphQL query (line 1, column 12)\n    mutation M($input: AddStarInput!) { addStar(input: $input) { clientMutationId } }\n" +
+				"               ^  variable $input got invalid value at \"starrableId\": field \"starrableId\" of required type \"ID!\" was not provided",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate(tt.query, tt.variables)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateMultipleErrors(t *testing.T) {
+	schema, err := Parse(testSchema)
+	assert.NoError(t, err)
+
+	err = schema.Validate(`{ viewer { logn } repository(owner: "cli") { name } }`, nil)
+	var verr *ValidationError
+	assert.ErrorAs(t, err, &verr)
+	assert.Equal(t, []ValidationErrorItem{
+		{Message: `field "logn" does not exist on type "User", did you mean "login"?`, Line: 1, Column: 12},
+		{Message: `argument "name" of type "String!" is required on field "repository"`, Line: 1, Column: 19},
+	}, verr.Errors)
+}
+
+func TestParseError(t *testing.T) {
+	_, err := Parse("type Query {\n  viewer: User!\n")
+	assert.EqualError(t, err, "failed to parse GraphQL schema (line 3, column 1)\n    \n    ^  expected name, found end of input")
+
+	_, err = ParseIntrospection([]byte(`{}`))
+	assert.EqualError(t, err, "failed to parse GraphQL introspection result: introspection result does not contain __schema")
+}
@@ -0,0 +1,159 @@
+package graphqlschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/cli/go-gh/v2/internal/graphql"
+)
+
+// validateVariables checks the provided variables against the variable
+// definitions of every operation in the document.
+func validateVariables(schema *graphql.Schema, doc *graphql.Document, variables map[string]interface{}) (graphql.Errors, error) {
+	// Round trip the variables through JSON so that the values are checked
+	// in the same form they will be sent to the API.
+	data, err := json.Marshal(variables)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	var errs graphql.Errors
+	for _, op := range doc.Operations {
+		for _, v := range op.Variables {
+			value, ok := values[v.Name]
+			if !ok || value == nil {
+				if v.Type.NonNull && v.DefaultValue == nil {
+					errs = append(errs, &graphql.Error{
+						Message: fmt.Sprintf("variable $%s of required type %q was not provided", v.Name, v.Type),
+						Pos:     v.Pos,
+					})
+				}
+				continue
+			}
+			if problem := checkValue(schema, v.Type, value, ""); problem != "" {
+				errs = append(errs, &graphql.Error{
+					Message: fmt.Sprintf("variable $%s got invalid value %s", v.Name, problem),
+					Pos:     v.Pos,
+				})
+			}
+		}
+	}
+	return errs, nil
+}
+
+// checkValue describes why the value cannot be coerced to the type,
+// or returns an empty string if it can.
+func checkValue(schema *graphql.Schema, t *graphql.Type, value interface{}, path string) string {
+	if value == nil {
+		if t.NonNull {
+			return invalid(t, value, path)
+		}
+		return ""
+	}
+
+	if t.Elem != nil {
+		list, ok := value.([]interface{})
+		if !ok {
+			// A single value is coerced to a list containing it.
+			return checkValue(schema, t.Elem, value, path)
+		}
+		for i, item := range list {
+			if problem := checkValue(schema, t.Elem, item, fmt.Sprintf("%s[%d]", path, i)); problem != "" {
+				return problem
+			}
+		}
+		return ""
+	}
+
+	def := schema.Types[t.Name]
+	if def == nil {
+		return ""
+	}
+	switch def.Kind {
+	case graphql.ScalarKind:
+		if !scalarAccepts(def.Name, value) {
+			return invalid(t, value, path)
+		}
+	case graphql.EnumKind:
+		s, ok := value.(string)
+		if !ok || def.EnumValue(s) == nil {
+			return invalid(t, value, path)
+		}
+	case graphql.InputObjectKind:
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return invalid(t, value, path)
+		}
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if def.InputField(name) == nil {
+				return fmt.Sprintf("at %q: field %q is not defined by type %q", join(path, name), name, def.Name)
+			}
+		}
+		for _, field := range def.InputFields {
+			fieldValue, ok := fields[field.Name]
+			if !ok && field.Type.NonNull && field.DefaultValue == nil {
+				return fmt.Sprintf("at %q: field %q of required type %q was not provided", join(path, field.Name), field.Name, field.Type)
+			}
+			if !ok {
+				continue
+			}
+			if problem := checkValue(schema, field.Type, fieldValue, join(path, field.Name)); problem != "" {
+				return problem
+			}
+		}
+	}
+	return ""
+}
+
+func scalarAccepts(name string, value interface{}) bool {
+	switch name {
+	case "Int":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n) && n >= math.MinInt32 && n <= math.MaxInt32
+	case "Float":
+		_, ok := value.(float64)
+		return ok
+	case "String":
+		_, ok := value.(string)
+		return ok
+	case "Boolean":
+		_, ok := value.(bool)
+		return ok
+	case "ID":
+		switch n := value.(type) {
+		case string:
+			return true
+		case float64:
+			return n == math.Trunc(n)
+		}
+		return false
+	}
+	// Custom scalars are serialized by the API and accept any value.
+	return true
+}
+
+func invalid(t *graphql.Type, value interface{}, path string) string {
+	found, _ := json.Marshal(value)
+	if path == "" {
+		return fmt.Sprintf("%s; expected type %q", found, t)
+	}
+	return fmt.Sprintf("%s at %q; expected type %q", found, path, t)
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,187 @@
+// Package update checks whether a newer release of a gh extension is
+// available so that its users can be notified.
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/internal/semver"
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/config"
+	"github.com/cli/go-gh/v2/pkg/repository"
+)
+
+const (
+	defaultInterval = 24 * time.Hour
+	noNotifierEnv   = "GH_NO_EXTENSION_UPDATE_NOTIFIER"
+	stateFileName   = "update-check.json"
+)
+
+var now = time.Now
+
+// Options holds options for checking for an update.
+type Options struct {
+	// Repo is the repository the extension is released from.
+	Repo repository.Repository
+
+	// CurrentVersion is the version of the running extension.
+	// Default is the version of the main module from the build information,
+	// which is set when the extension is installed with "go install".
+	CurrentVersion string
+
+	// Client is used to fetch the latest release.
+	// Default is a client for the host of Repo.
+	Client *api.RESTClient
+
+	// StateFile records when the last check happened and the release it found.
+	// Default is a file in the extension's directory under config.StateDir.
+	StateFile string
+
+	// Interval is the minimum time between checks.
+	// Default is 24 hours.
+	Interval time.Duration
+}
+
+// Release is a release of an extension.
+type Release struct {
+	Version     string    `json:"tag_name"`
+	URL         string    `json:"html_url"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// Notice describes an available update.
+type Notice struct {
+	Repo           repository.Repository
+	CurrentVersion string
+	Latest         Release
+}
+
+// String returns a message about the update suitable for printing to stderr.
+func (n *Notice) String() string {
+	name := strings.TrimPrefix(n.Repo.Name, "gh-")
+	return fmt.Sprintf("A new release of %s is available: %s → %s\nTo upgrade, run: gh extension upgrade %s\n%s\n",
+		n.Repo.Name,
+		strings.TrimPrefix(n.CurrentVersion, "v"),
+		strings.TrimPrefix(n.Latest.Version, "v"),
+		name,
+		n.Latest.URL)
+}
+
+type state struct {
+	CheckedAt     time.Time `json:"checked_at"`
+	LatestRelease Release   `json:"latest_release"`
+}
+
+// Check returns a Notice if a release newer than the current version exists.
+// If the last check happened less than Interval ago, the release found by that
+// check is used instead of fetching the latest release again. A check that
+// fails to fetch the latest release is recorded as finding no release, so it
+// is not retried until Interval has passed either. It returns nil
+// if the current version is not a release version such as for development
+// builds, or if the GH_NO_EXTENSION_UPDATE_NOTIFIER environment variable is set.
+func Check(ctx context.Context, opts Options) (*Notice, error) {
+	if os.Getenv(noNotifierEnv) != "" {
+		return nil, nil
+	}
+	if opts.CurrentVersion == "" {
+		opts.CurrentVersion = buildVersion()
+	}
+	current, err := semver.Parse(opts.CurrentVersion)
+	if err != nil {
+		return nil, nil
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultInterval
+	}
+	if opts.StateFile == "" {
+		opts.StateFile = filepath.Join(config.StateDir(), "extensions", opts.Repo.Name, stateFileName)
+	}
+
+	if s, err := readState(opts.StateFile); err == nil && now().Sub(s.CheckedAt) < opts.Interval {
+		return newNotice(opts, current, s.LatestRelease), nil
+	}
+
+	if opts.Client == nil {
+		opts.Client, err = api.NewRESTClient(api.ClientOptions{Host: opts.Repo.Host})
+		if err != nil {
+			return nil, err
+		}
+	}
+	release, err := latestRelease(ctx, opts.Client, opts.Repo)
+	if err != nil {
+		// Record failed checks too, so that a repository without releases or
+		// an unreachable host is not queried again until Interval has passed.
+		if ctx.Err() == nil {
+			_ = writeState(opts.StateFile, state{CheckedAt: now()})
+		}
+		return nil, err
+	}
+	if err := writeState(opts.StateFile, state{CheckedAt: now(), LatestRelease: *release}); err != nil {
+		return nil, err
+	}
+
+	return newNotice(opts, current, *release), nil
+}
+
+// newNotice returns a Notice if release is newer than current, or nil.
+func newNotice(opts Options, current semver.Version, release Release) *Notice {
+	latest, err := semver.Parse(release.Version)
+	if err != nil || latest.Compare(current) <= 0 {
+		return nil
+	}
+	return &Notice{Repo: opts.Repo, CurrentVersion: opts.CurrentVersion, Latest: release}
+}
+
+func latestRelease(ctx context.Context, client *api.RESTClient, repo repository.Repository) (*Release, error) {
+	var release Release
+	path := fmt.Sprintf("repos/%s/%s/releases/latest", repo.Owner, repo.Name)
+	if err := client.DoWithContext(ctx, http.MethodGet, path, nil, &release); err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// buildVersion returns the version of the main module,
+// or an empty string if it is unknown.
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	return info.Main.Version
+}
+
+func readState(filename string) (*state, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var s state
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+	if s.CheckedAt.IsZero() {
+		return nil, errors.New("missing check time")
+	}
+	return &s, nil
+}
+
+func writeState(filename string, s state) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, b, 0600)
+}
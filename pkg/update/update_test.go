@@ -0,0 +1,198 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/api/apitest"
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRepo = repository.Repository{Host: "github.com", Owner: "monalisa", Name: "gh-triage"}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentVersion string
+		latestVersion  string
+		state          string
+		env            string
+		wantNotice     bool
+		wantCalled     bool
+	}{
+		{
+			name:           "newer release",
+			currentVersion: "v1.2.0",
+			latestVersion:  "v1.3.0",
+			wantNotice:     true,
+			wantCalled:     true,
+		},
+		{
+			name:           "up to date",
+			currentVersion: "v1.3.0",
+			latestVersion:  "v1.3.0",
+			wantCalled:     true,
+		},
+		{
+			name:           "prerelease of latest",
+			currentVersion: "v1.3.0-rc.1",
+			latestVersion:  "v1.3.0",
+			wantNotice:     true,
+			wantCalled:     true,
+		},
+		{
+			name:           "development build",
+			currentVersion: "(devel)",
+			latestVersion:  "v1.3.0",
+		},
+		{
+			name:           "checked recently",
+			currentVersion: "v1.2.0",
+			latestVersion:  "v1.3.0",
+			state:          `{"checked_at": "2024-01-01T12:00:00Z"}`,
+		},
+		{
+			name:           "checked recently with newer release",
+			currentVersion: "v1.2.0",
+			latestVersion:  "v1.3.0",
+			state:          `{"checked_at": "2024-01-01T12:00:00Z", "latest_release": {"tag_name": "v1.3.0"}}`,
+			wantNotice:     true,
+		},
+		{
+			name:           "checked recently and up to date",
+			currentVersion: "v1.3.0",
+			latestVersion:  "v1.3.0",
+			state:          `{"checked_at": "2024-01-01T12:00:00Z", "latest_release": {"tag_name": "v1.3.0"}}`,
+		},
+		{
+			name:           "checked a while ago",
+			currentVersion: "v1.2.0",
+			latestVersion:  "v1.3.0",
+			state:          `{"checked_at": "2023-12-30T12:00:00Z"}`,
+			wantNotice:     true,
+			wantCalled:     true,
+		},
+		{
+			name:           "notifier disabled",
+			currentVersion: "v1.2.0",
+			latestVersion:  "v1.3.0",
+			env:            "1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubNow(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+			t.Setenv("GH_NO_EXTENSION_UPDATE_NOTIFIER", tt.env)
+			stateFile := filepath.Join(t.TempDir(), "state", stateFileName)
+			if tt.state != "" {
+				assert.NoError(t, os.MkdirAll(filepath.Dir(stateFile), 0o755))
+				assert.NoError(t, os.WriteFile(stateFile, []byte(tt.state), 0o600))
+			}
+
+			s := apitest.NewServer("token")
+			s.HandleFunc("GET", "/repos/{owner}/{repo}/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"tag_name": %q, "html_url": "https://github.com/monalisa/gh-triage/releases/tag/%s"}`, tt.latestVersion, tt.latestVersion)
+			})
+			client, err := api.NewRESTClient(s.ClientOptions())
+			assert.NoError(t, err)
+
+			notice, err := Check(context.Background(), Options{
+				Repo:           testRepo,
+				CurrentVersion: tt.currentVersion,
+				Client:         client,
+				StateFile:      stateFile,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCalled, s.Called("GET", "/repos/monalisa/gh-triage/releases/latest"))
+			if !tt.wantNotice {
+				assert.Nil(t, notice)
+				return
+			}
+			assert.Equal(t, tt.latestVersion, notice.Latest.Version)
+			if !tt.wantCalled {
+				return
+			}
+
+			// The check is recorded so that the next one is skipped.
+			s2, err := readState(stateFile)
+			assert.NoError(t, err)
+			assert.Equal(t, now(), s2.CheckedAt)
+			assert.Equal(t, tt.latestVersion, s2.LatestRelease.Version)
+		})
+	}
+}
+
+func TestCheckError(t *testing.T) {
+	stubNow(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	s := apitest.NewServer("token")
+	client, err := api.NewRESTClient(s.ClientOptions())
+	assert.NoError(t, err)
+
+	stateFile := filepath.Join(t.TempDir(), stateFileName)
+	opts := Options{
+		Repo:           testRepo,
+		CurrentVersion: "v1.2.0",
+		Client:         client,
+		StateFile:      stateFile,
+	}
+	_, err = Check(context.Background(), opts)
+	var httpErr *api.HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+
+	// The failed check is recorded so that the next one is skipped.
+	st, err := readState(stateFile)
+	assert.NoError(t, err)
+	assert.Equal(t, now(), st.CheckedAt)
+	assert.Equal(t, Release{}, st.LatestRelease)
+
+	notice, err := Check(context.Background(), opts)
+	assert.NoError(t, err)
+	assert.Nil(t, notice)
+	assert.Len(t, s.Calls(), 1)
+}
+
+func TestCheckCanceled(t *testing.T) {
+	s := apitest.NewServer("token")
+	client, err := api.NewRESTClient(s.ClientOptions())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stateFile := filepath.Join(t.TempDir(), stateFileName)
+	_, err = Check(ctx, Options{
+		Repo:           testRepo,
+		CurrentVersion: "v1.2.0",
+		Client:         client,
+		StateFile:      stateFile,
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NoFileExists(t, stateFile)
+}
+
+func TestNoticeString(t *testing.T) {
+	n := &Notice{
+		Repo:           testRepo,
+		CurrentVersion: "v1.2.0",
+		Latest:         Release{Version: "v1.3.0", URL: "https://github.com/monalisa/gh-triage/releases/tag/v1.3.0"},
+	}
+	assert.Equal(t, "A new release of gh-triage is available: 1.2.0 → 1.3.0\nTo upgrade, run: gh extension upgrade triage\nhttps://github.com/monalisa/gh-triage/releases/tag/v1.3.0\n", n.String())
+}
+
+func stubNow(t *testing.T, tm time.Time) {
+	t.Helper()
+	old := now
+	now = func() time.Time { return tm }
+	t.Cleanup(func() {
+		now = old
+	})
+}
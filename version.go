@@ -0,0 +1,157 @@
+package gh
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/v2/internal/semver"
+)
+
+var versionRE = regexp.MustCompile(`gh version (\S+)(?: \((\d{4}-\d{2}-\d{2})\))?`)
+
+// describeRE matches the suffix that "git describe" appends to the tagged
+// version of a build from source, such as "-19-gabc123".
+var describeRE = regexp.MustCompile(`-(\d+-g[0-9a-f]+)$`)
+
+var versionCache = struct {
+	sync.Mutex
+	m map[string]*Version
+}{m: map[string]*Version{}}
+
+// Version is the version of an installed gh.
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+
+	// Build is the "git describe" suffix, such as "19-gabc123", of a build
+	// from source made after the tagged version. Such a build satisfies the
+	// same minimum versions as the tagged version.
+	Build string
+
+	// BuildDate is the date gh was built, or the zero time if it is unknown.
+	BuildDate time.Time
+
+	// Dev reports whether gh was built from source without a version,
+	// in which case it is assumed to satisfy every minimum version.
+	Dev bool
+}
+
+// String returns the version, such as "2.40.1", or "DEV" for development builds.
+func (v *Version) String() string {
+	if v.Dev {
+		return "DEV"
+	}
+	if v.Build != "" {
+		return v.semver().String() + "-" + v.Build
+	}
+	return v.semver().String()
+}
+
+// AtLeast reports whether v is the same as or newer than minimum,
+// which is a version such as "2.40" or "2.40.1".
+func (v *Version) AtLeast(minimum string) (bool, error) {
+	want, err := semver.Parse(minimum)
+	if err != nil {
+		return false, err
+	}
+	return v.Dev || v.semver().Compare(want) >= 0, nil
+}
+
+func (v *Version) semver() semver.Version {
+	return semver.Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch, Prerelease: v.Prerelease}
+}
+
+// VersionError is returned by RequireVersion when the installed gh is older
+// than the minimum version.
+type VersionError struct {
+	Installed *Version
+	Minimum   string
+}
+
+// Allow VersionError to satisfy error interface.
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("gh %s or later is required, but gh %s is installed; upgrade gh to continue: https://github.com/cli/cli#installation", e.Minimum, e.Installed)
+}
+
+// CurrentVersion returns the version of the gh executable found by Path, as reported
+// by "gh --version". The version is cached for the lifetime of the process.
+func CurrentVersion(ctx context.Context) (*Version, error) {
+	ghExe, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	versionCache.Lock()
+	v, ok := versionCache.m[ghExe]
+	versionCache.Unlock()
+	if ok {
+		return v, nil
+	}
+
+	var stdout bytes.Buffer
+	if err := run(ctx, ghExe, ExecOptions{Stdout: &stdout}, []string{"--version"}); err != nil {
+		return nil, err
+	}
+	v, err = parseVersion(stdout.String())
+	if err != nil {
+		return nil, err
+	}
+
+	versionCache.Lock()
+	versionCache.m[ghExe] = v
+	versionCache.Unlock()
+	return v, nil
+}
+
+// RequireVersion returns a *VersionError if the installed gh is older than minimum,
+// which is a version such as "2.40" or "2.40.1".
+func RequireVersion(ctx context.Context, minimum string) error {
+	v, err := CurrentVersion(ctx)
+	if err != nil {
+		return err
+	}
+	ok, err := v.AtLeast(minimum)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &VersionError{Installed: v, Minimum: minimum}
+	}
+	return nil
+}
+
+func parseVersion(output string) (*Version, error) {
+	m := versionRE.FindStringSubmatch(output)
+	if m == nil {
+		return nil, fmt.Errorf("unable to determine gh version from %q", output)
+	}
+
+	v := &Version{}
+	if m[2] != "" {
+		if date, err := time.Parse("2006-01-02", m[2]); err == nil {
+			v.BuildDate = date
+		}
+	}
+	if m[1] == "DEV" {
+		v.Dev = true
+		return v, nil
+	}
+
+	version := m[1]
+	if dm := describeRE.FindStringSubmatchIndex(version); dm != nil {
+		v.Build = version[dm[2]:dm[3]]
+		version = version[:dm[0]]
+	}
+	sv, err := semver.Parse(version)
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine gh version: %w", err)
+	}
+	v.Major, v.Minor, v.Patch, v.Prerelease = sv.Major, sv.Minor, sv.Patch, sv.Prerelease
+	return v, nil
+}
@@ -0,0 +1,138 @@
+package gh
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    *Version
+		wantErr string
+	}{
+		{
+			name:   "release",
+			output: "gh version 2.40.1 (2023-12-13)\nhttps://github.com/cli/cli/releases/tag/v2.40.1\n",
+			want:   &Version{Major: 2, Minor: 40, Patch: 1, BuildDate: time.Date(2023, 12, 13, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:   "prerelease without date",
+			output: "gh version 2.41.0-pre.1\n",
+			want:   &Version{Major: 2, Minor: 41, Prerelease: "pre.1"},
+		},
+		{
+			name:   "build from source after a tag",
+			output: "gh version 2.40.1-19-gabc123 (2024-01-05)\n",
+			want:   &Version{Major: 2, Minor: 40, Patch: 1, Build: "19-gabc123", BuildDate: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:   "build from source after a prerelease tag",
+			output: "gh version 2.41.0-pre.1-3-g0f1e2d\n",
+			want:   &Version{Major: 2, Minor: 41, Prerelease: "pre.1", Build: "3-g0f1e2d"},
+		},
+		{
+			name:   "development build",
+			output: "gh version DEV\n",
+			want:   &Version{Dev: true},
+		},
+		{
+			name:    "unexpected output",
+			output:  "hub version 2.14.2\n",
+			wantErr: `unable to determine gh version from "hub version 2.14.2\n"`,
+		},
+		{
+			name:    "invalid version",
+			output:  "gh version 2.x\n",
+			wantErr: `unable to determine gh version: invalid version "2.x"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := parseVersion(tt.output)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, v)
+			if !v.Dev {
+				assert.Equal(t, strings.Fields(tt.output)[2], v.String())
+			}
+		})
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	v := &Version{Major: 2, Minor: 40, Patch: 1}
+	tests := []struct {
+		minimum string
+		want    bool
+	}{
+		{minimum: "2.40.1", want: true},
+		{minimum: "2.40", want: true},
+		{minimum: "v2.39.9", want: true},
+		{minimum: "2.40.2", want: false},
+		{minimum: "3", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.minimum, func(t *testing.T) {
+			ok, err := v.AtLeast(tt.minimum)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+
+	_, err := v.AtLeast("latest")
+	assert.EqualError(t, err, `invalid version "latest"`)
+
+	ok, err := (&Version{Major: 2, Minor: 40, Patch: 1, Build: "19-gabc123"}).AtLeast("2.40.1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = (&Version{Dev: true}).AtLeast("99.0.0")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestRequireVersion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the gh executable")
+	}
+	dir := t.TempDir()
+	ghExe := filepath.Join(dir, "gh")
+	calls := filepath.Join(dir, "calls")
+	script := strings.Join([]string{
+		"#!/bin/sh",
+		"echo called >> " + calls,
+		`echo "gh version 2.30.0 (2023-05-30)"`,
+		`echo "https://github.com/cli/cli/releases/tag/v2.30.0"`,
+	}, "\n")
+	assert.NoError(t, os.WriteFile(ghExe, []byte(script), 0o755))
+	t.Setenv("GH_PATH", ghExe)
+
+	err := RequireVersion(context.Background(), "2.30")
+	assert.NoError(t, err)
+
+	err = RequireVersion(context.Background(), "2.40.0")
+	var versionErr *VersionError
+	assert.ErrorAs(t, err, &versionErr)
+	assert.Equal(t, "2.40.0", versionErr.Minimum)
+	assert.Equal(t, "2.30.0", versionErr.Installed.String())
+	assert.EqualError(t, err, "gh 2.40.0 or later is required, but gh 2.30.0 is installed; upgrade gh to continue: https://github.com/cli/cli#installation")
+
+	// The version is only determined once.
+	b, err := os.ReadFile(calls)
+	assert.NoError(t, err)
+	assert.Equal(t, "called\n", string(b))
+}